@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
+)
+
+// Spotify ne fournit pas de concerts : les relations dates-lieux viennent d'un fichier JSON
+// (concertsFile) qui associe à chaque artiste, désigné par son ID Spotify, ses dates de concerts
+// par lieu au format Groupie. Sans fichier, aucun artiste n'a de concert connu.
+
+// ConcertEntry est l'entrée d'un artiste dans le fichier des concerts
+type ConcertEntry struct {
+	SpotifyID      string              `json:"spotifyId"`
+	Name           string              `json:"name"`           // Nom de l'artiste : seule clé si spotifyId est absent
+	DatesLocations map[string][]string `json:"datesLocations"` // "paris-france": ["23-08-2019"]
+}
+
+// LoadConcerts lit le fichier des concerts ; un chemin vide donne une liste vide
+func LoadConcerts(path string) ([]ConcertEntry, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture des concerts : %w", err)
+	}
+	var entries []ConcertEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("fichier des concerts illisible (%s) : %w", path, err)
+	}
+	for i, entry := range entries {
+		if entry.SpotifyID == "" && entry.Name == "" {
+			return nil, fmt.Errorf("%s : entrée %d sans spotifyId ni name", path, i+1)
+		}
+	}
+	return entries, nil
+}
+
+// SetConcerts remplace les concerts connus ; les détails en cache, qui les contiennent, sont oubliés
+func (s *SpotifyClient) SetConcerts(entries []ConcertEntry) {
+	byID := make(map[string]map[string][]string)
+	byName := make(map[string]map[string][]string)
+	for _, entry := range entries {
+		if entry.SpotifyID != "" {
+			byID[entry.SpotifyID] = entry.DatesLocations
+		} else {
+			byName[utils.FoldText(entry.Name)] = entry.DatesLocations
+		}
+	}
+
+	s.mu.Lock()
+	s.concertsByID = byID
+	s.concertsByName = byName
+	s.details = make(map[int]detailEntry)
+	s.mu.Unlock()
+}
+
+// HasConcerts indique qu'un fichier de concerts a été chargé
+func (s *SpotifyClient) HasConcerts() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.concertsByID)+len(s.concertsByName) > 0
+}
+
+// Relations retourne les relations dates-lieux des artistes donnés, sous leur ID du catalogue.
+// Un artiste est retrouvé par son ID Spotify, ou par son nom si l'entrée n'a pas d'ID.
+func (s *SpotifyClient) Relations(artists []models.Artist) []models.Relation {
+	s.mu.Lock()
+	byID, byName := s.concertsByID, s.concertsByName
+	s.mu.Unlock()
+
+	relations := []models.Relation{}
+	for _, artist := range artists {
+		datesLocations, ok := byID[artist.SpotifyID]
+		if !ok || artist.SpotifyID == "" {
+			datesLocations, ok = byName[utils.FoldText(artist.Name)]
+		}
+		if ok && len(datesLocations) > 0 {
+			relations = append(relations, models.Relation{ID: artist.ID, DatesLocations: datesLocations})
+		}
+	}
+	sort.Slice(relations, func(i, j int) bool { return relations[i].ID < relations[j].ID })
+	return relations
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Erreurs du client Spotify, à reconnaître avec errors.Is (les handlers en déduisent le statut HTTP)
+var (
+	ErrNotFound            = errors.New("introuvable")
+	ErrUnauthenticated     = errors.New("identifiants Spotify absents ou refusés")
+	ErrRateLimited         = errors.New("limite d'appels Spotify atteinte")
+	ErrUpstreamUnavailable = errors.New("Spotify indisponible")
+	ErrTimeout             = errors.New("délai dépassé")
+)
+
+// UpstreamError est une réponse d'erreur de Spotify ; errors.Is la rapproche de l'erreur
+// correspondant à son statut (404 : ErrNotFound, 401 : ErrUnauthenticated, 429 : ErrRateLimited,
+// 504 : ErrTimeout, autres : ErrUpstreamUnavailable)
+type UpstreamError struct {
+	Endpoint   string        // Nom de l'appel ("search", "artist"...)
+	StatusCode int           // Statut renvoyé par Spotify
+	RetryAfter time.Duration // Délai demandé avant un nouvel essai (Retry-After), 0 si absent
+	Body       string        // Début du corps de la réponse
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("erreur API Spotify %s (code %d): %s", e.Endpoint, e.StatusCode, e.Body)
+}
+
+// Is rapproche l'erreur de la sentinelle correspondant à son statut
+func (e *UpstreamError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized:
+		return target == ErrUnauthenticated
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusGatewayTimeout:
+		return target == ErrTimeout
+	}
+	return target == ErrUpstreamUnavailable
+}
+
+// RetryAfter retourne le délai avant un nouvel essai demandé par Spotify (0 s'il n'en a pas donné)
+func RetryAfter(err error) time.Duration {
+	var upstream *UpstreamError
+	if errors.As(err, &upstream) {
+		return upstream.RetryAfter
+	}
+	return 0
+}
+
+// newStatusError construit l'erreur d'une réponse de Spotify au statut inattendu
+func newStatusError(resp *http.Response, endpoint string) *UpstreamError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return &UpstreamError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Body:       string(body),
+	}
+}
+
+// parseRetryAfter lit un en-tête Retry-After (secondes ou date HTTP)
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && time.Until(at) > 0 {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// transportError classe l'échec d'un appel sans réponse : délai dépassé (ErrTimeout) ou
+// Spotify injoignable (ErrUpstreamUnavailable). L'annulation par l'appelant reste telle quelle.
+func transportError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+}
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"groupie-tracker-ng/models"
+)
+
+// albumsBatchSize est le nombre maximum d'IDs acceptés par /albums?ids=
+const albumsBatchSize = 20
+
+// Loader regroupe les appels Spotify d'une même requête (GraphQL) : chaque ressource n'est
+// demandée qu'une fois, les appels indépendants partent en parallèle et les titres
+// des albums sont chargés par lots de 20 (/albums?ids=) plutôt qu'album par album.
+// Titres populaires, albums et artistes similaires viennent d'abord du cache des détails du
+// client (mêmes données que les pages d'artiste) et y sont enregistrés après un appel à Spotify.
+// Le token est obtenu avant de lancer les appels parallèles, qui ne font ensuite que le lire.
+type Loader struct {
+	ctx         context.Context // Contexte de la requête (annulation, identifiant dans les journaux)
+	client      *SpotifyClient
+	concurrency int // Appels simultanés (réglage du client à la création du Loader)
+	topTracks   memo[[]models.TrackInfo]
+	albums      memo[[]models.AlbumInfo]
+	related     memo[[]models.RelatedArtistInfo]
+	albumTracks memo[[]models.TrackInfo]
+}
+
+// NewLoader crée un Loader pour une requête
+func (s *SpotifyClient) NewLoader(ctx context.Context) *Loader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Loader{ctx: ctx, client: s, concurrency: s.concurrency}
+}
+
+// TopTracks retourne les titres populaires de chaque artiste (ID Spotify)
+func (l *Loader) TopTracks(spotifyIDs []string) (map[string][]models.TrackInfo, error) {
+	if err := l.client.authenticate(l.ctx); err != nil {
+		return nil, err
+	}
+	return l.topTracks.loadEach(spotifyIDs, l.concurrency, func(id string) ([]models.TrackInfo, error) {
+		return l.client.artistTopTracks(l.ctx, id)
+	})
+}
+
+// Albums retourne les albums de chaque artiste (ID Spotify)
+func (l *Loader) Albums(spotifyIDs []string) (map[string][]models.AlbumInfo, error) {
+	if err := l.client.authenticate(l.ctx); err != nil {
+		return nil, err
+	}
+	return l.albums.loadEach(spotifyIDs, l.concurrency, func(id string) ([]models.AlbumInfo, error) {
+		return l.client.artistAlbums(l.ctx, id)
+	})
+}
+
+// RelatedArtists retourne les artistes similaires de chaque artiste (ID Spotify)
+func (l *Loader) RelatedArtists(spotifyIDs []string) (map[string][]models.RelatedArtistInfo, error) {
+	if err := l.client.authenticate(l.ctx); err != nil {
+		return nil, err
+	}
+	return l.related.loadEach(spotifyIDs, l.concurrency, func(id string) ([]models.RelatedArtistInfo, error) {
+		return l.client.relatedArtists(l.ctx, id)
+	})
+}
+
+// AlbumTracks retourne les titres de chaque album (ID Spotify), par lots de 20 albums
+func (l *Loader) AlbumTracks(albumIDs []string) (map[string][]models.TrackInfo, error) {
+	if err := l.client.authenticate(l.ctx); err != nil {
+		return nil, err
+	}
+	return l.albumTracks.loadBatch(albumIDs, albumsBatchSize, l.concurrency, func(ids []string) (map[string][]models.TrackInfo, error) {
+		return l.client.getAlbumsTracks(l.ctx, ids)
+	})
+}
+
+// resourceCache mémorise une ressource Spotify par ID d'artiste pendant la durée de validité
+// du catalogue ; les erreurs ne sont pas mémorisées
+type resourceCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]resourceEntry[T]
+}
+
+// resourceEntry est une ressource en cache
+type resourceEntry[T any] struct {
+	value    T
+	cachedAt time.Time
+}
+
+// load retourne la ressource en cache si elle a moins de ttl, sinon l'obtient par fetch
+// et l'enregistre ; name désigne le cache dans les métriques
+func (c *resourceCache[T]) load(name, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.cachedAt) < ttl {
+		ObserveCache(name, true)
+		return entry.value, nil
+	}
+	ObserveCache(name, false)
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]resourceEntry[T])
+	}
+	c.entries[key] = resourceEntry[T]{value: value, cachedAt: time.Now()}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// forget retire des clés du cache (toutes si aucune n'est donnée)
+func (c *resourceCache[T]) forget(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(keys) == 0 {
+		c.entries = nil
+	}
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// artistTopTracks retourne les titres populaires d'un artiste (ID Spotify), en cache si possible
+func (s *SpotifyClient) artistTopTracks(ctx context.Context, spotifyID string) ([]models.TrackInfo, error) {
+	return s.topTracks.load("artist_top_tracks", spotifyID, s.CacheTTL(), func() ([]models.TrackInfo, error) {
+		return s.getArtistTopTracks(ctx, spotifyID)
+	})
+}
+
+// artistAlbums retourne les albums d'un artiste (ID Spotify), en cache si possible
+func (s *SpotifyClient) artistAlbums(ctx context.Context, spotifyID string) ([]models.AlbumInfo, error) {
+	return s.albums.load("artist_albums", spotifyID, s.CacheTTL(), func() ([]models.AlbumInfo, error) {
+		return s.getArtistAlbums(ctx, spotifyID)
+	})
+}
+
+// relatedArtists retourne les artistes similaires d'un artiste (ID Spotify), en cache si possible
+func (s *SpotifyClient) relatedArtists(ctx context.Context, spotifyID string) ([]models.RelatedArtistInfo, error) {
+	return s.related.load("artist_related", spotifyID, s.CacheTTL(), func() ([]models.RelatedArtistInfo, error) {
+		return s.getRelatedArtists(ctx, spotifyID)
+	})
+}
+
+// clearResources vide les ressources en cache d'un artiste (ID Spotify), ou de tous
+func (s *SpotifyClient) clearResources(spotifyIDs ...string) {
+	s.topTracks.forget(spotifyIDs...)
+	s.albums.forget(spotifyIDs...)
+	s.related.forget(spotifyIDs...)
+}
+
+// memo mémorise des résultats par clé ; les erreurs sont mémorisées aussi
+// pour ne pas relancer un appel qui vient d'échouer
+type memo[T any] struct {
+	mu     sync.Mutex
+	values map[string]T
+	errs   map[string]error
+}
+
+// missing enregistre les clés demandées et retourne celles qui restent à charger (dédoublonnées)
+func (m *memo[T]) missing(keys []string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.values == nil {
+		m.values = make(map[string]T)
+		m.errs = make(map[string]error)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	var todo []string
+	for _, key := range keys {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := m.values[key]; ok {
+			continue
+		}
+		if _, ok := m.errs[key]; ok {
+			continue
+		}
+		todo = append(todo, key)
+	}
+	return todo
+}
+
+// store mémorise le résultat d'une clé
+func (m *memo[T]) store(key string, value T, err error) {
+	m.mu.Lock()
+	if err != nil {
+		m.errs[key] = err
+	} else {
+		m.values[key] = value
+	}
+	m.mu.Unlock()
+}
+
+// results retourne les valeurs des clés demandées et la première erreur rencontrée
+func (m *memo[T]) results(keys []string) (map[string]T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]T, len(keys))
+	var firstErr error
+	for _, key := range keys {
+		if value, ok := m.values[key]; ok {
+			out[key] = value
+		} else if err, ok := m.errs[key]; ok && firstErr == nil {
+			firstErr = fmt.Errorf("%s : %w", key, err)
+		}
+	}
+	return out, firstErr
+}
+
+// loadEach charge une à une les clés absentes, au plus concurrency à la fois
+func (m *memo[T]) loadEach(keys []string, concurrency int, fetch func(string) (T, error)) (map[string]T, error) {
+	todo := m.missing(keys)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, key := range todo {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			value, err := fetch(key)
+			m.store(key, value, err)
+		}(key)
+	}
+	wg.Wait()
+	return m.results(keys)
+}
+
+// loadBatch charge les clés absentes par lots de size, au plus concurrency lots à la fois
+func (m *memo[T]) loadBatch(keys []string, size, concurrency int, fetch func([]string) (map[string]T, error)) (map[string]T, error) {
+	todo := m.missing(keys)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for start := 0; start < len(todo); start += size {
+		batch := todo[start:min(start+size, len(todo))]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			values, err := fetch(batch)
+			for _, key := range batch {
+				value, ok := values[key]
+				switch {
+				case err != nil:
+					m.store(key, value, err)
+				case !ok:
+					m.store(key, value, fmt.Errorf("absent de la réponse Spotify : %w", ErrNotFound))
+				default:
+					m.store(key, value, nil)
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+	return m.results(keys)
+}
+
+// spotifyAlbumsResp est la réponse de /albums?ids= (albums complets avec leurs titres)
+type spotifyAlbumsResp struct {
+	Albums []*struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Tracks struct {
+			Items []struct {
+				Name         string `json:"name"`
+				DurationMs   int    `json:"duration_ms"`
+				PreviewURL   string `json:"preview_url"`
+				ExternalURLs struct {
+					Spotify string `json:"spotify"`
+				} `json:"external_urls"`
+				Artists []struct {
+					Name string `json:"name"`
+				} `json:"artists"`
+			} `json:"items"`
+		} `json:"tracks"`
+	} `json:"albums"`
+}
+
+// getAlbumsTracks récupère en un appel les titres de plusieurs albums (20 au plus)
+func (s *SpotifyClient) getAlbumsTracks(ctx context.Context, albumIDs []string) (map[string][]models.TrackInfo, error) {
+	if err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/albums?ids=%s&market=%s", SpotifyAPIURL, strings.Join(albumIDs, ","), s.marketCode())
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.authorize(req)
+	resp, err := s.do(req, "albums")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp, "albums")
+	}
+	var data spotifyAlbumsResp
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]models.TrackInfo, len(data.Albums))
+	for _, album := range data.Albums {
+		if album == nil {
+			continue // ID inconnu de Spotify
+		}
+		tracks := make([]models.TrackInfo, 0, len(album.Tracks.Items))
+		for _, t := range album.Tracks.Items {
+			info := models.TrackInfo{
+				Name:       t.Name,
+				SpotifyURL: t.ExternalURLs.Spotify,
+				AlbumName:  album.Name,
+				DurationMs: t.DurationMs,
+				PreviewURL: t.PreviewURL,
+				Artists:    make([]string, 0, len(t.Artists)),
+			}
+			for _, a := range t.Artists {
+				info.Artists = append(info.Artists, a.Name)
+			}
+			tracks = append(tracks, info)
+		}
+		out[album.ID] = tracks
+	}
+	return out, nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"groupie-tracker-ng/metrics"
+)
+
+// Métriques des appels Spotify et du cache du catalogue (publiées sur /metrics)
+var (
+	spotifyRequests = metrics.NewCounterVec("groupie_spotify_requests_total",
+		"Appels à l'API Spotify par endpoint et statut HTTP (error : échec réseau ou délai dépassé).",
+		"endpoint", "status")
+	spotifyDuration = metrics.NewHistogramVec("groupie_spotify_request_duration_seconds",
+		"Durée des appels à l'API Spotify par endpoint.",
+		nil, "endpoint")
+	tokenRefreshes = metrics.NewCounterVec("groupie_spotify_token_refreshes_total",
+		"Demandes de token d'accès Spotify par résultat (success, failure).",
+		"result")
+	catalogueRefreshDuration = metrics.NewHistogramVec("groupie_catalogue_refresh_duration_seconds",
+		"Durée des rafraîchissements du catalogue (liste et enrichissement des artistes) par résultat.",
+		[]float64{1, 5, 10, 20, 30, 60, 120, 300}, "result")
+	cacheRequests = metrics.NewCounterVec("groupie_cache_requests_total",
+		"Consultations des caches par cache et résultat (hit, miss).",
+		"cache", "result")
+)
+
+// observeSpotifyCall enregistre un appel Spotify ; status vaut 0 si aucune réponse n'a été reçue
+func observeSpotifyCall(endpoint string, status int, elapsed time.Duration) {
+	label := "error"
+	if status > 0 {
+		label = strconv.Itoa(status)
+	}
+	spotifyRequests.Inc(endpoint, label)
+	spotifyDuration.Observe(elapsed.Seconds(), endpoint)
+}
+
+// ObserveCache enregistre une consultation de cache (hit ou miss)
+func ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.Inc(cache, result)
+}
+
+// resultLabel retourne le libellé de résultat d'une opération
+func resultLabel(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
@@ -1,18 +1,21 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
 )
 
 const (
@@ -20,18 +23,83 @@ const (
 	SpotifyAPIURL  = "https://api.spotify.com/v1"
 )
 
-const artistsCacheTTL = 5 * time.Minute
+// Réglages par défaut du client (voir Settings)
+const (
+	DefaultMarket      = "FR"
+	DefaultTimeout     = 10 * time.Second
+	DefaultCacheTTL    = 5 * time.Minute // Validité de la liste d'artistes en cache
+	DefaultConcurrency = 4
+)
+
+// Settings regroupe les réglages du client Spotify
+type Settings struct {
+	ClientID     string
+	ClientSecret string
+	Market       string            // Code pays des catalogues (titres, albums)
+	Timeout      time.Duration     // Délai maximum d'un appel
+	CacheTTL     time.Duration     // Validité de la liste d'artistes en cache
+	Concurrency  int               // Appels simultanés d'un Loader
+	Transport    http.RoundTripper // Transport des appels (nil = transport par défaut ; remplacé dans les tests)
+}
 
 type SpotifyClient struct {
 	clientID     string
 	clientSecret string
 	httpClient   *http.Client
+	market       string
+	cacheTTL     time.Duration
+	concurrency  int
 	accessToken  string
 	tokenExpiry  time.Time
 	// Cache liste artistes pour que l'ID reste stable (détail par ID)
 	mu           sync.Mutex
 	cachedArtists []models.Artist
 	cacheTime     time.Time
+	// Détails déjà construits, par ID ; vidé à chaque rafraîchissement de la liste (les ID changent)
+	details map[int]detailEntry
+	// Ressources des détails par ID Spotify, partagées avec les Loader (GraphQL) ; vidées avec details
+	topTracks resourceCache[[]models.TrackInfo]
+	albums    resourceCache[[]models.AlbumInfo]
+	related   resourceCache[[]models.RelatedArtistInfo]
+	// Concerts du fichier concertsFile, par ID Spotify et par nom replié (voir SetConcerts)
+	concertsByID   map[string]map[string][]string
+	concertsByName map[string]map[string][]string
+	// Fonctions appelées après chaque rafraîchissement de la liste (index de recherche, etc.)
+	refreshHooks []func([]models.Artist)
+	// Rafraîchissement en cours, partagé par les appels concurrents de FetchArtists (nil sinon)
+	refreshing *artistsRefresh
+	// Durée de vie des rafraîchissements partagés (voir SetLifetime)
+	lifetime context.Context
+	// Fonctions appelées à chaque étape d'un rafraîchissement (suivi de progression)
+	progressHooks []func(RefreshEvent)
+	// Résultats des dernières authentification et rafraîchissements (voir Status)
+	authErr         error
+	lastRefresh     time.Time
+	refreshFailures []RefreshFailure // Plus récent en dernier, maxRefreshFailures au plus
+}
+
+// artistsRefresh est un rafraîchissement en cours ; done est fermé une fois artists et err connus
+type artistsRefresh struct {
+	done    chan struct{}
+	artists []models.Artist
+	err     error
+}
+
+// Étapes d'un rafraîchissement du catalogue
+const (
+	RefreshStarted  = "start"
+	RefreshProgress = "progress"
+	RefreshDone     = "done"
+	RefreshFailed   = "failed"
+)
+
+// RefreshEvent décrit l'avancement d'un rafraîchissement du catalogue
+type RefreshEvent struct {
+	Stage  string `json:"stage"`
+	Done   int    `json:"done"`  // Artistes déjà enrichis
+	Total  int    `json:"total"` // 0 tant que la liste n'est pas connue
+	Artist string `json:"artist,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
 type SpotifyTokenResponse struct {
@@ -46,7 +114,14 @@ type SpotifyArtist struct {
 	Images []struct {
 		URL string `json:"url"`
 	} `json:"images"`
-	Genres []string `json:"genres"`
+	Genres     []string `json:"genres"`
+	Popularity int      `json:"popularity"`
+	Followers  struct {
+		Total int `json:"total"`
+	} `json:"followers"`
+	ExternalURLs struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
 }
 
 type SpotifySearchResponse struct {
@@ -82,6 +157,7 @@ type spotifyTopTracksResp struct {
 		DurationMs  int    `json:"duration_ms"`
 		PreviewURL  string `json:"preview_url"`
 		ExternalURLs struct { Spotify string `json:"spotify"` } `json:"external_urls"`
+		Artists []struct { Name string `json:"name"` } `json:"artists"`
 		Album struct {
 			Name   string `json:"name"`
 			Images []struct { URL string `json:"url"` } `json:"images"`
@@ -124,13 +200,7 @@ func NewClient() *SpotifyClient {
 		clientSecret = "your_client_secret_here"
 	}
 
-	return &SpotifyClient{
-		clientID:     clientID,
-		clientSecret: clientSecret,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
+	return NewSpotifyClient(clientID, clientSecret)
 }
 
 // NewSpotifyClient crée un nouveau client Spotify avec credentials explicites
@@ -139,52 +209,136 @@ func NewSpotifyClient(clientID, clientSecret string) *SpotifyClient {
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		market:      DefaultMarket,
+		cacheTTL:    DefaultCacheTTL,
+		concurrency: DefaultConcurrency,
+		details:     make(map[int]detailEntry),
+		lifetime:    context.Background(),
 	}
 }
 
+// SetLifetime borne les rafraîchissements du catalogue : détachés des requêtes qui les
+// provoquent, ils s'arrêtent à l'annulation de ctx (arrêt du serveur)
+func (s *SpotifyClient) SetLifetime(ctx context.Context) {
+	s.mu.Lock()
+	s.lifetime = ctx
+	s.mu.Unlock()
+}
+
+// Configure applique des réglages au client, au démarrage ou au rechargement de la configuration.
+// Les identifiants vides et les valeurs nulles gardent le réglage actuel.
+func (s *SpotifyClient) Configure(settings Settings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if (settings.ClientID != "" && settings.ClientID != s.clientID) ||
+		(settings.ClientSecret != "" && settings.ClientSecret != s.clientSecret) {
+		// Nouveaux identifiants (rechargement de la configuration) : redemander un token
+		s.accessToken = ""
+		s.tokenExpiry = time.Time{}
+		s.authErr = nil
+	}
+	if settings.ClientID != "" {
+		s.clientID = settings.ClientID
+	}
+	if settings.ClientSecret != "" {
+		s.clientSecret = settings.ClientSecret
+	}
+	if settings.Market != "" {
+		s.market = settings.Market
+	}
+	if settings.Timeout > 0 || settings.Transport != nil {
+		// Nouveau client : les appels en cours gardent l'ancien délai et l'ancien transport
+		client := *s.httpClient
+		if settings.Timeout > 0 {
+			client.Timeout = settings.Timeout
+		}
+		if settings.Transport != nil {
+			client.Transport = settings.Transport
+		}
+		s.httpClient = &client
+	}
+	if settings.CacheTTL > 0 {
+		s.cacheTTL = settings.CacheTTL
+	}
+	if settings.Concurrency > 0 {
+		s.concurrency = settings.Concurrency
+	}
+}
+
+// CacheTTL retourne la durée de validité de la liste d'artistes en cache
+func (s *SpotifyClient) CacheTTL() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cacheTTL
+}
+
+// marketCode retourne le marché des catalogues (code pays)
+func (s *SpotifyClient) marketCode() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.market
+}
+
+// authorize ajoute le token d'accès courant à une requête
+func (s *SpotifyClient) authorize(req *http.Request) {
+	s.mu.Lock()
+	token := s.accessToken
+	s.mu.Unlock()
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
 // authenticate obtient un token d'accès Spotify
-func (s *SpotifyClient) authenticate() error {
+func (s *SpotifyClient) authenticate(ctx context.Context) (err error) {
 	// Vérifier si le token est encore valide (avec une marge de 1 minute)
-	if s.accessToken != "" && time.Now().Add(1*time.Minute).Before(s.tokenExpiry) {
+	s.mu.Lock()
+	valid := s.accessToken != "" && time.Now().Add(1*time.Minute).Before(s.tokenExpiry)
+	clientID, clientSecret := s.clientID, s.clientSecret
+	s.mu.Unlock()
+	if valid {
 		return nil
 	}
+	defer func() {
+		tokenRefreshes.Inc(resultLabel(err))
+		s.recordAuth(err)
+	}()
 
 	// Vérifier que les credentials sont configurés
-	if s.clientID == "" || s.clientID == "your_client_id_here" {
-		return fmt.Errorf("SPOTIFY_CLIENT_ID non configuré - définissez la variable d'environnement")
+	if clientID == "" || clientID == "your_client_id_here" {
+		return fmt.Errorf("%w : SPOTIFY_CLIENT_ID non configuré - définissez la variable d'environnement", ErrUnauthenticated)
 	}
-	if s.clientSecret == "" || s.clientSecret == "your_client_secret_here" {
-		return fmt.Errorf("SPOTIFY_CLIENT_SECRET non configuré - définissez la variable d'environnement")
+	if clientSecret == "" || clientSecret == "your_client_secret_here" {
+		return fmt.Errorf("%w : SPOTIFY_CLIENT_SECRET non configuré - définissez la variable d'environnement", ErrUnauthenticated)
 	}
 
 	// Préparer les données pour la requête
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 
-	req, err := http.NewRequest("POST", SpotifyAuthURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", SpotifyAuthURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return fmt.Errorf("erreur lors de la création de la requête: %w", err)
 	}
 
 	// Encoder les credentials en base64
-	credentials := base64.StdEncoding.EncodeToString([]byte(s.clientID + ":" + s.clientSecret))
+	credentials := base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
 	req.Header.Set("Authorization", "Basic "+credentials)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "token")
 	if err != nil {
 		return fmt.Errorf("erreur réseau lors de l'authentification: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode == http.StatusUnauthorized {
-			return fmt.Errorf("credentials Spotify invalides - vérifiez SPOTIFY_CLIENT_ID et SPOTIFY_CLIENT_SECRET")
+		upstream := newStatusError(resp, "token")
+		// Spotify répond 400 (invalid_client) ou 401 à des identifiants refusés
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusBadRequest {
+			return fmt.Errorf("%w : vérifiez SPOTIFY_CLIENT_ID et SPOTIFY_CLIENT_SECRET (code %d): %s", ErrUnauthenticated, upstream.StatusCode, upstream.Body)
 		}
-		return fmt.Errorf("erreur d'authentification Spotify (code %d): %s", resp.StatusCode, string(body))
+		return fmt.Errorf("erreur d'authentification Spotify: %w", upstream)
 	}
 
 	var tokenResp SpotifyTokenResponse
@@ -196,40 +350,116 @@ func (s *SpotifyClient) authenticate() error {
 		return fmt.Errorf("token d'accès vide reçu de Spotify")
 	}
 
-	s.accessToken = tokenResp.AccessToken
 	// Expiry avec une marge de sécurité
 	expirySeconds := tokenResp.ExpiresIn
 	if expirySeconds == 0 {
 		expirySeconds = 3600 // Par défaut 1 heure
 	}
+	s.mu.Lock()
+	s.accessToken = tokenResp.AccessToken
 	s.tokenExpiry = time.Now().Add(time.Duration(expirySeconds) * time.Second)
+	s.mu.Unlock()
 
 	return nil
 }
 
+// do envoie une requête à Spotify et journalise l'appel (endpoint, statut, durée) avec
+// l'identifiant de la requête de page qui l'a provoqué, porté par le contexte de req
+func (s *SpotifyClient) do(req *http.Request, endpoint string) (*http.Response, error) {
+	s.mu.Lock()
+	client := s.httpClient
+	s.mu.Unlock()
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+	logger := utils.Logger(req.Context()).With(
+		"endpoint", endpoint,
+		"method", req.Method,
+		"duration_ms", elapsed.Milliseconds(),
+	)
+	if err != nil {
+		observeSpotifyCall(endpoint, 0, elapsed)
+		logger.Warn("appel Spotify en échec", "error", err)
+		return nil, transportError(err)
+	}
+	observeSpotifyCall(endpoint, resp.StatusCode, elapsed)
+	logger.Info("appel Spotify", "status", resp.StatusCode)
+	return resp, nil
+}
+
 // ============================================
 // MÉTHODES COMPATIBLES AVEC L'ANCIENNE API
 // ============================================
 
-// FetchArtists récupère la liste d'artistes populaires et la met en cache (IDs stables)
-func (s *SpotifyClient) FetchArtists() ([]models.Artist, error) {
+// FetchArtists récupère la liste d'artistes populaires et la met en cache (IDs stables).
+// Un rafraîchissement profite à tous : il n'est pas interrompu si la requête de ctx se termine
+// (seulement à l'arrêt du serveur, voir SetLifetime),
+// et les appels qui arrivent pendant qu'il tourne l'attendent (ou rendent la main avec ctx.Err()).
+func (s *SpotifyClient) FetchArtists(ctx context.Context) ([]models.Artist, error) {
 	s.mu.Lock()
-	if len(s.cachedArtists) > 0 && time.Since(s.cacheTime) < artistsCacheTTL {
+	if len(s.cachedArtists) > 0 && time.Since(s.cacheTime) < s.cacheTTL {
 		out := make([]models.Artist, len(s.cachedArtists))
 		copy(out, s.cachedArtists)
 		s.mu.Unlock()
+		ObserveCache("artists", true)
 		return out, nil
 	}
+	ObserveCache("artists", false)
+
+	// Cache froid : un seul rafraîchissement (et une seule suite d'événements de progression),
+	// que les appels concurrents attendent au lieu d'en lancer un chacun
+	if refresh := s.refreshing; refresh != nil {
+		s.mu.Unlock()
+		select {
+		case <-refresh.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if refresh.err != nil {
+			return nil, refresh.err
+		}
+		out := make([]models.Artist, len(refresh.artists))
+		copy(out, refresh.artists)
+		return out, nil
+	}
+	refresh := &artistsRefresh{done: make(chan struct{})}
+	s.refreshing = refresh
+	lifetime := s.lifetime
 	s.mu.Unlock()
 
+	refreshCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(lifetime, cancel)
+	start := time.Now()
+	artists, err := s.refreshArtists(refreshCtx)
+	stop()
+	cancel()
+	catalogueRefreshDuration.Observe(time.Since(start).Seconds(), resultLabel(err))
+	s.recordRefresh(err)
+
+	refresh.artists, refresh.err = artists, err
+	s.mu.Lock()
+	s.refreshing = nil
+	s.mu.Unlock()
+	close(refresh.done)
+	return artists, err
+}
+
+// refreshArtists recharge la liste d'artistes depuis Spotify, l'enrichit et la met en cache
+func (s *SpotifyClient) refreshArtists(ctx context.Context) ([]models.Artist, error) {
+	s.notifyProgress(RefreshEvent{Stage: RefreshStarted})
+
 	// Récupérer les artistes depuis Spotify
-	spotifyArtists, err := s.FetchPopularArtists()
+	spotifyArtists, err := s.FetchPopularArtists(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération des artistes Spotify: %w", err)
+		err = fmt.Errorf("erreur lors de la récupération des artistes Spotify: %w", err)
+		s.notifyProgress(RefreshEvent{Stage: RefreshFailed, Error: err.Error()})
+		return nil, err
 	}
 
 	if len(spotifyArtists) == 0 {
-		return nil, fmt.Errorf("aucun artiste récupéré depuis Spotify")
+		err = fmt.Errorf("aucun artiste récupéré depuis Spotify")
+		s.notifyProgress(RefreshEvent{Stage: RefreshFailed, Error: err.Error()})
+		return nil, err
 	}
 
 	// Convertir les artistes Spotify en modèles Artist avec année de création
@@ -259,9 +489,13 @@ func (s *SpotifyClient) FetchArtists() ([]models.Artist, error) {
 			artist.Genres = make([]string, len(sa.Genres))
 			copy(artist.Genres, sa.Genres)
 		}
+		artist.Popularity = sa.Popularity
+		artist.Followers = sa.Followers.Total
+		artist.SpotifyID = sa.ID
+		artist.SpotifyURL = sa.ExternalURLs.Spotify
 		
 		// Récupérer le premier album pour obtenir l'année de création
-		firstAlbum, firstAlbumDate, creationYear := s.getFirstAlbumAndYear(sa.ID)
+		firstAlbum, firstAlbumDate, creationYear := s.getFirstAlbumAndYear(ctx, sa.ID)
 		if firstAlbum != "" {
 			artist.FirstAlbum = firstAlbum
 		}
@@ -272,26 +506,109 @@ func (s *SpotifyClient) FetchArtists() ([]models.Artist, error) {
 			artist.CreationDate = creationYear
 		}
 		
+		// Interrompu (arrêt du serveur) : ne pas mettre en cache des artistes à moitié enrichis
+		if err := ctx.Err(); err != nil {
+			err = fmt.Errorf("rafraîchissement interrompu: %w", err)
+			s.notifyProgress(RefreshEvent{Stage: RefreshFailed, Error: err.Error()})
+			return nil, err
+		}
+
 		artists = append(artists, artist)
+		s.notifyProgress(RefreshEvent{Stage: RefreshProgress, Done: i + 1, Total: len(spotifyArtists), Artist: sa.Name})
 	}
 
 	// Mettre en cache
 	s.mu.Lock()
 	s.cachedArtists = artists
 	s.cacheTime = time.Now()
+	s.details = make(map[int]detailEntry)
+	hooks := s.refreshHooks
 	s.mu.Unlock()
+	s.clearResources()
+
+	// Prévenir les abonnés (chacun reçoit sa propre copie)
+	for _, hook := range hooks {
+		snapshot := make([]models.Artist, len(artists))
+		copy(snapshot, artists)
+		hook(snapshot)
+	}
+	s.notifyProgress(RefreshEvent{Stage: RefreshDone, Done: len(artists), Total: len(artists)})
 	
 	return artists, nil
 }
 
-// FetchArtistDetail récupère les détails d'un artiste par ID (utilise le cache pour cohérence)
-func (s *SpotifyClient) FetchArtistDetail(artistID int) (*models.ArtistDetail, error) {
+// OnArtistsRefresh enregistre une fonction appelée avec la nouvelle liste à chaque rafraîchissement du cache
+func (s *SpotifyClient) OnArtistsRefresh(hook func([]models.Artist)) {
+	s.mu.Lock()
+	s.refreshHooks = append(s.refreshHooks, hook)
+	s.mu.Unlock()
+}
+
+// OnRefreshProgress enregistre une fonction appelée à chaque étape d'un rafraîchissement du cache
+func (s *SpotifyClient) OnRefreshProgress(hook func(RefreshEvent)) {
+	s.mu.Lock()
+	s.progressHooks = append(s.progressHooks, hook)
+	s.mu.Unlock()
+}
+
+// notifyProgress prévient les abonnés de l'avancement d'un rafraîchissement
+func (s *SpotifyClient) notifyProgress(event RefreshEvent) {
+	s.mu.Lock()
+	hooks := s.progressHooks
+	s.mu.Unlock()
+	for _, hook := range hooks {
+		hook(event)
+	}
+}
+
+// CachedArtists retourne la liste en cache sans la rafraîchir (nil si jamais chargée)
+// et indique si elle est encore valide
+func (s *SpotifyClient) CachedArtists() ([]models.Artist, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cachedArtists) == 0 {
+		return nil, false
+	}
+	out := make([]models.Artist, len(s.cachedArtists))
+	copy(out, s.cachedArtists)
+	return out, time.Since(s.cacheTime) < s.cacheTTL
+}
+
+// FetchArtistDetail récupère les détails d'un artiste par ID (utilise le cache pour cohérence).
+// Le détail est gardé en cache pendant la durée de validité du catalogue ; il est partagé et ne doit pas être modifié.
+func (s *SpotifyClient) FetchArtistDetail(ctx context.Context, artistID int) (*models.ArtistDetail, error) {
 	// Utiliser le cache pour retrouver le même artiste que sur la liste
-	artists, err := s.FetchArtists()
+	artists, err := s.FetchArtists(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la récupération des artistes: %w", err)
 	}
 
+	s.mu.Lock()
+	entry, ok := s.details[artistID]
+	loadedAt := s.cacheTime
+	s.mu.Unlock()
+	if ok && time.Since(entry.cachedAt) < s.CacheTTL() {
+		ObserveCache("artist_detail", true)
+		return entry.detail, nil
+	}
+	ObserveCache("artist_detail", false)
+
+	detail, err := s.loadArtistDetail(ctx, artists, artistID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if s.cacheTime.Equal(loadedAt) {
+		// Pas de mise en cache si la liste a été rafraîchie entre-temps : l'ID désigne peut-être un autre artiste
+		s.details[artistID] = detailEntry{detail: detail, cachedAt: time.Now()}
+	}
+	s.mu.Unlock()
+	return detail, nil
+}
+
+// loadArtistDetail construit le détail d'un artiste du catalogue (artists) depuis Spotify
+func (s *SpotifyClient) loadArtistDetail(ctx context.Context, artists []models.Artist, artistID int) (*models.ArtistDetail, error) {
+
 	var artist *models.Artist
 	for i := range artists {
 		if artists[i].ID == artistID {
@@ -300,7 +617,7 @@ func (s *SpotifyClient) FetchArtistDetail(artistID int) (*models.ArtistDetail, e
 		}
 	}
 	if artist == nil {
-		return nil, fmt.Errorf("artiste avec ID %d non trouvé", artistID)
+		return nil, fmt.Errorf("artiste avec ID %d %w", artistID, ErrNotFound)
 	}
 
 	// Copie pour ne pas modifier le cache
@@ -318,23 +635,40 @@ func (s *SpotifyClient) FetchArtistDetail(artistID int) (*models.ArtistDetail, e
 		RelatedArtists: []models.RelatedArtistInfo{},
 	}
 
-	// Enrichir avec l'API Spotify
-	spotifyFull, _ := s.searchArtistByName(artist.Name)
+	// Concerts : relations dates-lieux du fichier des concerts
+	for _, rel := range s.Relations([]models.Artist{*artist}) {
+		for location, dates := range rel.DatesLocations {
+			detail.Relations[location] = dates
+			detail.Locations = append(detail.Locations, location)
+			detail.ConcertDates = append(detail.ConcertDates, dates...)
+		}
+		sort.Strings(detail.Locations)
+	}
+
+	// Enrichir avec l'API Spotify : un artiste introuvable garde les données du catalogue,
+	// les autres erreurs (identifiants, limite d'appels, délai...) remontent à l'appelant
+	spotifyFull, err := s.searchArtistByName(ctx, artist.Name)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("recherche de %q sur Spotify : %w", artist.Name, err)
+	}
 	if spotifyFull != nil {
-		full, _ := s.GetArtistByID(spotifyFull.ID)
+		full, err := s.GetArtistByID(ctx, spotifyFull.ID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("détail Spotify de %q : %w", artist.Name, err)
+		}
 		if full != nil {
 			if len(full.Images) > 0 {
 				artistCopy.Image = full.Images[0].URL
 			}
 			artistCopy.SpotifyURL = full.ExternalURLs.Spotify
-			artistCopy.Genres = full.Genres
+			artistCopy.Genres = append([]string{}, full.Genres...) // Jamais null en JSON
 			artistCopy.Popularity = full.Popularity
 			artistCopy.Followers = full.Followers.Total
 			detail.Artist = artistCopy
 
 			// Mettre à jour l'année de création et le premier album si pas déjà défini
 			if detail.Artist.CreationDate == 0 || detail.Artist.FirstAlbum == "" {
-				firstAlbum, firstAlbumDate, creationYear := s.getFirstAlbumAndYear(full.ID)
+				firstAlbum, firstAlbumDate, creationYear := s.getFirstAlbumAndYear(ctx, full.ID)
 				if creationYear > 0 {
 					detail.Artist.CreationDate = creationYear
 				}
@@ -347,13 +681,13 @@ func (s *SpotifyClient) FetchArtistDetail(artistID int) (*models.ArtistDetail, e
 			}
 			
 			// Top titres, albums, artistes similaires (ignorer erreurs pour ne pas casser la page)
-			if tracks, err := s.getArtistTopTracks(full.ID); err == nil && len(tracks) > 0 {
+			if tracks, err := s.artistTopTracks(ctx, full.ID); err == nil && len(tracks) > 0 {
 				detail.TopTracks = tracks
 			}
-			if albums, err := s.getArtistAlbums(full.ID); err == nil && len(albums) > 0 {
+			if albums, err := s.artistAlbums(ctx, full.ID); err == nil && len(albums) > 0 {
 				detail.Albums = albums
 			}
-			if related, err := s.getRelatedArtists(full.ID); err == nil && len(related) > 0 {
+			if related, err := s.relatedArtists(ctx, full.ID); err == nil && len(related) > 0 {
 				detail.RelatedArtists = related
 			}
 		}
@@ -361,14 +695,9 @@ func (s *SpotifyClient) FetchArtistDetail(artistID int) (*models.ArtistDetail, e
 	return detail, nil
 }
 
-// FetchRelations retourne une liste vide (Spotify ne fournit pas ces données)
-func (s *SpotifyClient) FetchRelations() ([]models.Relation, error) {
-	return []models.Relation{}, nil
-}
-
 // FindArtistByName recherche un artiste par son nom (remplace l'ancien FindArtistByName)
-func (s *SpotifyClient) FindArtistByName(name string) (*models.Artist, error) {
-	spotifyArtist, err := s.searchArtistByName(name)
+func (s *SpotifyClient) FindArtistByName(ctx context.Context, name string) (*models.Artist, error) {
+	spotifyArtist, err := s.searchArtistByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -384,6 +713,7 @@ func (s *SpotifyClient) FindArtistByName(name string) (*models.Artist, error) {
 		Locations:    "",
 		ConcertDates: "",
 		Relations:    "",
+		Genres:       []string{},
 	}
 
 	if len(spotifyArtist.Images) > 0 {
@@ -398,29 +728,28 @@ func (s *SpotifyClient) FindArtistByName(name string) (*models.Artist, error) {
 // ============================================
 
 // searchArtistByName recherche un artiste par son nom
-func (s *SpotifyClient) searchArtistByName(artistName string) (*SpotifyArtist, error) {
-	if err := s.authenticate(); err != nil {
+func (s *SpotifyClient) searchArtistByName(ctx context.Context, artistName string) (*SpotifyArtist, error) {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
 	searchURL := fmt.Sprintf("%s/search?q=%s&type=artist&limit=1", SpotifyAPIURL, url.QueryEscape(artistName))
 
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la création de la requête: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
+	s.authorize(req)
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "search")
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la requête: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erreur API Spotify (code %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError(resp, "search")
 	}
 
 	var searchResp SpotifySearchResponse
@@ -429,15 +758,15 @@ func (s *SpotifyClient) searchArtistByName(artistName string) (*SpotifyArtist, e
 	}
 
 	if len(searchResp.Artists.Items) == 0 {
-		return nil, fmt.Errorf("artiste non trouvé sur Spotify")
+		return nil, fmt.Errorf("artiste %q %w sur Spotify", artistName, ErrNotFound)
 	}
 
 	return &searchResp.Artists.Items[0], nil
 }
 
 // SearchArtists recherche plusieurs artistes sur Spotify
-func (s *SpotifyClient) SearchArtists(query string, limit int) ([]SpotifyArtist, error) {
-	if err := s.authenticate(); err != nil {
+func (s *SpotifyClient) SearchArtists(ctx context.Context, query string, limit int) ([]SpotifyArtist, error) {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, fmt.Errorf("authentification requise: %w", err)
 	}
 
@@ -449,18 +778,18 @@ func (s *SpotifyClient) SearchArtists(query string, limit int) ([]SpotifyArtist,
 		limit = 1
 	}
 
-	searchURL := fmt.Sprintf("%s/search?q=%s&type=artist&limit=%d&market=FR", 
-		SpotifyAPIURL, url.QueryEscape(query), limit)
+	searchURL := fmt.Sprintf("%s/search?q=%s&type=artist&limit=%d&market=%s", 
+		SpotifyAPIURL, url.QueryEscape(query), limit, s.marketCode())
 
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la création de la requête: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
+	s.authorize(req)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "search")
 	if err != nil {
 		return nil, fmt.Errorf("erreur réseau lors de la recherche: %w", err)
 	}
@@ -468,13 +797,15 @@ func (s *SpotifyClient) SearchArtists(query string, limit int) ([]SpotifyArtist,
 
 	if resp.StatusCode == http.StatusUnauthorized {
 		// Token expiré, réessayer une fois
+		s.mu.Lock()
 		s.accessToken = "" // Forcer le renouvellement
-		if err := s.authenticate(); err != nil {
+		s.mu.Unlock()
+		if err := s.authenticate(ctx); err != nil {
 			return nil, fmt.Errorf("erreur de ré-authentification: %w", err)
 		}
 		// Réessayer la requête
-		req.Header.Set("Authorization", "Bearer "+s.accessToken)
-		resp, err = s.httpClient.Do(req)
+		s.authorize(req)
+		resp, err = s.do(req, "search")
 		if err != nil {
 			return nil, fmt.Errorf("erreur réseau lors de la recherche (retry): %w", err)
 		}
@@ -482,8 +813,7 @@ func (s *SpotifyClient) SearchArtists(query string, limit int) ([]SpotifyArtist,
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erreur API Spotify (code %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError(resp, "search")
 	}
 
 	var searchResp SpotifySearchResponse
@@ -503,29 +833,28 @@ func (s *SpotifyClient) SearchArtists(query string, limit int) ([]SpotifyArtist,
 }
 
 // GetArtistByID récupère un artiste complet par son ID Spotify
-func (s *SpotifyClient) GetArtistByID(artistID string) (*SpotifyArtistFull, error) {
-	if err := s.authenticate(); err != nil {
+func (s *SpotifyClient) GetArtistByID(ctx context.Context, artistID string) (*SpotifyArtistFull, error) {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
 	artistURL := fmt.Sprintf("%s/artists/%s", SpotifyAPIURL, artistID)
 
-	req, err := http.NewRequest("GET", artistURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", artistURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la création de la requête: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
+	s.authorize(req)
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "artist")
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la requête: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erreur API Spotify (code %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError(resp, "artist")
 	}
 
 	var artist SpotifyArtistFull
@@ -536,24 +865,24 @@ func (s *SpotifyClient) GetArtistByID(artistID string) (*SpotifyArtistFull, erro
 	return &artist, nil
 }
 
-// getArtistTopTracks récupère les titres les plus populaires d'un artiste (market FR)
-func (s *SpotifyClient) getArtistTopTracks(spotifyArtistID string) ([]models.TrackInfo, error) {
-	if err := s.authenticate(); err != nil {
+// getArtistTopTracks récupère les titres les plus populaires d'un artiste (marché configuré)
+func (s *SpotifyClient) getArtistTopTracks(ctx context.Context, spotifyArtistID string) ([]models.TrackInfo, error) {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("%s/artists/%s/top-tracks?market=FR", SpotifyAPIURL, spotifyArtistID)
-	req, err := http.NewRequest("GET", u, nil)
+	u := fmt.Sprintf("%s/artists/%s/top-tracks?market=%s", SpotifyAPIURL, spotifyArtistID, s.marketCode())
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
-	resp, err := s.httpClient.Do(req)
+	s.authorize(req)
+	resp, err := s.do(req, "artist_top_tracks")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("top tracks: %d", resp.StatusCode)
+		return nil, newStatusError(resp, "artist_top_tracks")
 	}
 	var data spotifyTopTracksResp
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -567,6 +896,10 @@ func (s *SpotifyClient) getArtistTopTracks(spotifyArtistID string) ([]models.Tra
 			AlbumName:  t.Album.Name,
 			DurationMs: t.DurationMs,
 			PreviewURL: t.PreviewURL,
+			Artists:    make([]string, 0, len(t.Artists)),
+		}
+		for _, a := range t.Artists {
+			info.Artists = append(info.Artists, a.Name)
 		}
 		out = append(out, info)
 	}
@@ -574,23 +907,23 @@ func (s *SpotifyClient) getArtistTopTracks(spotifyArtistID string) ([]models.Tra
 }
 
 // getArtistAlbums récupère les albums d'un artiste (max 20)
-func (s *SpotifyClient) getArtistAlbums(spotifyArtistID string) ([]models.AlbumInfo, error) {
-	if err := s.authenticate(); err != nil {
+func (s *SpotifyClient) getArtistAlbums(ctx context.Context, spotifyArtistID string) ([]models.AlbumInfo, error) {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("%s/artists/%s/albums?limit=20&market=FR", SpotifyAPIURL, spotifyArtistID)
-	req, err := http.NewRequest("GET", u, nil)
+	u := fmt.Sprintf("%s/artists/%s/albums?limit=20&market=%s", SpotifyAPIURL, spotifyArtistID, s.marketCode())
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
-	resp, err := s.httpClient.Do(req)
+	s.authorize(req)
+	resp, err := s.do(req, "artist_albums")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("albums: %d", resp.StatusCode)
+		return nil, newStatusError(resp, "artist_albums")
 	}
 	var data spotifyArtistAlbumsResp
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -603,6 +936,7 @@ func (s *SpotifyClient) getArtistAlbums(spotifyArtistID string) ([]models.AlbumI
 			img = a.Images[0].URL
 		}
 		out = append(out, models.AlbumInfo{
+			ID:          a.ID,
 			Name:        a.Name,
 			SpotifyURL:  a.ExternalURLs.Spotify,
 			ReleaseDate: a.ReleaseDate,
@@ -614,19 +948,19 @@ func (s *SpotifyClient) getArtistAlbums(spotifyArtistID string) ([]models.AlbumI
 }
 
 // getFirstAlbumAndYear récupère le premier album d'un artiste, sa date de sortie et l'année de création
-func (s *SpotifyClient) getFirstAlbumAndYear(spotifyArtistID string) (string, string, int) {
-	if err := s.authenticate(); err != nil {
+func (s *SpotifyClient) getFirstAlbumAndYear(ctx context.Context, spotifyArtistID string) (string, string, int) {
+	if err := s.authenticate(ctx); err != nil {
 		return "", "", 0
 	}
 	
 	// Récupérer les albums triés par date (les plus anciens en premier)
-	u := fmt.Sprintf("%s/artists/%s/albums?limit=50&market=FR&include_groups=album", SpotifyAPIURL, spotifyArtistID)
-	req, err := http.NewRequest("GET", u, nil)
+	u := fmt.Sprintf("%s/artists/%s/albums?limit=50&market=%s&include_groups=album", SpotifyAPIURL, spotifyArtistID, s.marketCode())
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return "", "", 0
 	}
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
-	resp, err := s.httpClient.Do(req)
+	s.authorize(req)
+	resp, err := s.do(req, "artist_albums")
 	if err != nil {
 		return "", "", 0
 	}
@@ -678,23 +1012,23 @@ func (s *SpotifyClient) getFirstAlbumAndYear(spotifyArtistID string) (string, st
 }
 
 // getRelatedArtists récupère les artistes similaires
-func (s *SpotifyClient) getRelatedArtists(spotifyArtistID string) ([]models.RelatedArtistInfo, error) {
-	if err := s.authenticate(); err != nil {
+func (s *SpotifyClient) getRelatedArtists(ctx context.Context, spotifyArtistID string) ([]models.RelatedArtistInfo, error) {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, err
 	}
 	u := fmt.Sprintf("%s/artists/%s/related-artists", SpotifyAPIURL, spotifyArtistID)
-	req, err := http.NewRequest("GET", u, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
-	resp, err := s.httpClient.Do(req)
+	s.authorize(req)
+	resp, err := s.do(req, "related_artists")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("related: %d", resp.StatusCode)
+		return nil, newStatusError(resp, "related_artists")
 	}
 	var data spotifyRelatedArtistsResp
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -710,16 +1044,22 @@ func (s *SpotifyClient) getRelatedArtists(spotifyArtistID string) ([]models.Rela
 			Name:       a.Name,
 			ImageURL:   img,
 			SpotifyURL: a.ExternalURLs.Spotify,
-			Genres:     a.Genres,
+			Genres:     append([]string{}, a.Genres...),
 		})
 	}
 	return out, nil
 }
 
+// stopsSearches indique qu'une erreur de recherche vaudra pour les suivantes (limite d'appels,
+// identifiants refusés, requête annulée) : inutile de continuer
+func stopsSearches(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnauthenticated) || errors.Is(err, context.Canceled)
+}
+
 // FetchPopularArtists récupère une liste d'artistes populaires depuis Spotify
-func (s *SpotifyClient) FetchPopularArtists() ([]SpotifyArtist, error) {
+func (s *SpotifyClient) FetchPopularArtists(ctx context.Context) ([]SpotifyArtist, error) {
 	// Vérifier l'authentification une seule fois
-	if err := s.authenticate(); err != nil {
+	if err := s.authenticate(ctx); err != nil {
 		return nil, fmt.Errorf("erreur d'authentification Spotify: %w", err)
 	}
 
@@ -751,8 +1091,11 @@ func (s *SpotifyClient) FetchPopularArtists() ([]SpotifyArtist, error) {
 			break
 		}
 		
-		artists, err := s.SearchArtists(query, 20)
+		artists, err := s.SearchArtists(ctx, query, 20)
 		if err != nil {
+			if stopsSearches(err) {
+				return nil, fmt.Errorf("recherche %q: %w", query, err)
+			}
 			// Continuer avec la requête suivante en cas d'erreur
 			continue
 		}
@@ -781,8 +1124,11 @@ func (s *SpotifyClient) FetchPopularArtists() ([]SpotifyArtist, error) {
 				break
 			}
 			
-			artists, err := s.SearchArtists(name, 5)
+			artists, err := s.SearchArtists(ctx, name, 5)
 			if err != nil {
+				if stopsSearches(err) {
+					return nil, fmt.Errorf("recherche %q: %w", name, err)
+				}
 				continue
 			}
 			
@@ -797,7 +1143,7 @@ func (s *SpotifyClient) FetchPopularArtists() ([]SpotifyArtist, error) {
 
 	// Dernière tentative : recherche générique si toujours pas assez
 	if len(allArtists) < 20 {
-		artists, err := s.SearchArtists("artist", 50)
+		artists, err := s.SearchArtists(ctx, "artist", 50)
 		if err != nil {
 			return nil, fmt.Errorf("impossible de récupérer des artistes: %w", err)
 		}
@@ -818,14 +1164,14 @@ func (s *SpotifyClient) FetchPopularArtists() ([]SpotifyArtist, error) {
 }
 
 // GetArtistInfo récupère les informations complètes d'un artiste
-func (s *SpotifyClient) GetArtistInfo(artistName string) (map[string]interface{}, error) {
-	spotifyArtist, err := s.searchArtistByName(artistName)
+func (s *SpotifyClient) GetArtistInfo(ctx context.Context, artistName string) (map[string]interface{}, error) {
+	spotifyArtist, err := s.searchArtistByName(ctx, artistName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Récupérer les informations complètes
-	fullArtist, err := s.GetArtistByID(spotifyArtist.ID)
+	fullArtist, err := s.GetArtistByID(ctx, spotifyArtist.ID)
 	if err != nil {
 		// Si erreur, utiliser les données de base
 		info := map[string]interface{}{
@@ -865,6 +1211,7 @@ func (s *SpotifyClient) ConvertSpotifyArtistToModel(sa SpotifyArtist, id int) mo
 		Locations:    "",
 		ConcertDates: "",
 		Relations:    "",
+		Genres:       []string{},
 	}
 
 	if len(sa.Images) > 0 {
@@ -875,8 +1222,8 @@ func (s *SpotifyClient) ConvertSpotifyArtistToModel(sa SpotifyArtist, id int) mo
 }
 
 // GetSpotifyIDFromArtistID récupère l'ID Spotify à partir de l'ID interne
-func (s *SpotifyClient) GetSpotifyIDFromArtistID(artistID int) (string, error) {
-	artists, err := s.FetchArtists()
+func (s *SpotifyClient) GetSpotifyIDFromArtistID(ctx context.Context, artistID int) (string, error) {
+	artists, err := s.FetchArtists(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -885,11 +1232,11 @@ func (s *SpotifyClient) GetSpotifyIDFromArtistID(artistID int) (string, error) {
 	// Pour simplifier, on va utiliser une recherche
 	if artistID > 0 && artistID <= len(artists) {
 		artist := artists[artistID-1]
-		spotifyArtist, err := s.searchArtistByName(artist.Name)
+		spotifyArtist, err := s.searchArtistByName(ctx, artist.Name)
 		if err == nil {
 			return spotifyArtist.ID, nil
 		}
 	}
 
-	return "", fmt.Errorf("ID Spotify non trouvé pour l'artiste %d", artistID)
+	return "", fmt.Errorf("ID Spotify de l'artiste %d %w", artistID, ErrNotFound)
 }
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"groupie-tracker-ng/models"
+)
+
+// États de la source de données (Spotify) rapportés par Status.Source
+const (
+	SourceOK              = "ok"
+	SourceUnknown         = "unknown"         // Aucun rafraîchissement ni authentification encore tentés
+	SourceUnavailable     = "unavailable"     // Dernier appel en échec (réseau, délai, erreur Spotify)
+	SourceUnauthenticated = "unauthenticated" // Identifiants absents ou refusés
+)
+
+// maxRefreshFailures est le nombre d'échecs de rafraîchissement conservés
+const maxRefreshFailures = 10
+
+// RefreshFailure est un rafraîchissement du catalogue en échec
+type RefreshFailure struct {
+	Time time.Time
+	Err  error
+}
+
+// detailEntry est un détail d'artiste en cache
+type detailEntry struct {
+	detail   *models.ArtistDetail
+	cachedAt time.Time
+}
+
+// CachedDetail décrit un détail d'artiste en cache
+type CachedDetail struct {
+	ID       int
+	Name     string
+	CachedAt time.Time
+}
+
+// Status est un instantané de l'état du client : caches, token d'accès et rafraîchissements
+type Status struct {
+	CatalogueSize int
+	CatalogueTime time.Time // Chargement du catalogue en cache (zéro s'il n'a jamais été chargé)
+	CacheTTL      time.Duration
+	TokenExpiry   time.Time        // Expiration du token d'accès (zéro si aucun n'a été obtenu)
+	AuthError     error            // Échec de la dernière demande de token (nil si elle a réussi)
+	LastRefresh   time.Time        // Dernier rafraîchissement du catalogue réussi
+	LastFailure   time.Time        // Dernier rafraîchissement en échec
+	LastError     error            // Erreur de ce dernier échec
+	Failures      []RefreshFailure // Derniers échecs, du plus récent au plus ancien
+	Details       []CachedDetail   // Détails d'artistes en cache, par ID
+}
+
+// Status retourne l'état actuel du client
+func (s *SpotifyClient) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Status{
+		CatalogueSize: len(s.cachedArtists),
+		CatalogueTime: s.cacheTime,
+		CacheTTL:      s.cacheTTL,
+		TokenExpiry:   s.tokenExpiry,
+		AuthError:     s.authErr,
+		LastRefresh:   s.lastRefresh,
+	}
+	for i := len(s.refreshFailures) - 1; i >= 0; i-- {
+		st.Failures = append(st.Failures, s.refreshFailures[i])
+	}
+	if len(st.Failures) > 0 {
+		st.LastFailure, st.LastError = st.Failures[0].Time, st.Failures[0].Err
+	}
+	for id, entry := range s.details {
+		st.Details = append(st.Details, CachedDetail{ID: id, Name: entry.detail.Name, CachedAt: entry.cachedAt})
+	}
+	sort.Slice(st.Details, func(i, j int) bool { return st.Details[i].ID < st.Details[j].ID })
+	return st
+}
+
+// recordAuth mémorise le résultat d'une demande de token
+func (s *SpotifyClient) recordAuth(err error) {
+	s.mu.Lock()
+	s.authErr = err
+	s.mu.Unlock()
+}
+
+// recordRefresh mémorise le résultat d'un rafraîchissement du catalogue
+func (s *SpotifyClient) recordRefresh(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.lastRefresh = time.Now()
+		return
+	}
+	s.refreshFailures = append(s.refreshFailures, RefreshFailure{Time: time.Now(), Err: err})
+	if extra := len(s.refreshFailures) - maxRefreshFailures; extra > 0 {
+		s.refreshFailures = append([]RefreshFailure(nil), s.refreshFailures[extra:]...)
+	}
+}
+
+// ExpireArtists invalide la liste d'artistes en cache, et elle seule : elle n'est pas vidée.
+// Le prochain appel à FetchArtists (ou à FetchArtistDetail, qui passe par lui) lance aussitôt
+// un rafraîchissement et l'attend ; d'ici là, seul CachedArtists sert encore l'ancienne liste,
+// signalée comme expirée. Les détails en cache ne sont pas touchés : c'est ce rafraîchissement,
+// s'il réussit, qui les vide. Rien n'est lancé par ExpireArtists elle-même.
+func (s *SpotifyClient) ExpireArtists() {
+	s.mu.Lock()
+	s.cacheTime = time.Time{}
+	s.mu.Unlock()
+}
+
+// EvictArtistDetail retire du cache le détail d'un artiste, ressources Spotify comprises
+// (titres, albums, artistes similaires, aussi servis par GraphQL) ; false s'il n'y était pas
+func (s *SpotifyClient) EvictArtistDetail(artistID int) bool {
+	s.mu.Lock()
+	entry, ok := s.details[artistID]
+	delete(s.details, artistID)
+	s.mu.Unlock()
+	if ok && entry.detail.Artist.SpotifyID != "" {
+		s.clearResources(entry.detail.Artist.SpotifyID)
+	}
+	return ok
+}
+
+// CatalogueLoaded indique si un catalogue est en cache (même expiré)
+func (st Status) CatalogueLoaded() bool {
+	return st.CatalogueSize > 0
+}
+
+// CatalogueFresh indique si le catalogue en cache est encore valide
+func (st Status) CatalogueFresh(now time.Time) bool {
+	return st.CatalogueLoaded() && now.Sub(st.CatalogueTime) < st.CacheTTL
+}
+
+// TokenValid indique si le token d'accès est encore valide
+func (st Status) TokenValid(now time.Time) bool {
+	return now.Before(st.TokenExpiry)
+}
+
+// Source retourne l'état de Spotify d'après la dernière authentification et le dernier rafraîchissement
+func (st Status) Source() string {
+	switch {
+	case errors.Is(st.AuthError, ErrUnauthenticated):
+		return SourceUnauthenticated
+	case st.AuthError != nil, st.LastFailure.After(st.LastRefresh):
+		return SourceUnavailable
+	case st.LastRefresh.IsZero() && st.TokenExpiry.IsZero():
+		return SourceUnknown
+	}
+	return SourceOK
+}
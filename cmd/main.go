@@ -1,27 +1,146 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"groupie-tracker-ng/config"
 	"groupie-tracker-ng/handlers"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 )
 
 func main() {
+	// Journaux structurés (JSON) ; les appels à log.Printf passent aussi par ce handler
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
 
-	fs := http.FileServer(http.Dir("./static"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
+	cfg, printConfig, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if printConfig {
+		// Afficher la configuration même invalide : c'est l'outil pour comprendre l'erreur
+		config.Print(os.Stdout, cfg)
+		if err != nil {
+			log.Fatalf("Configuration invalide:\n%v", err)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatalf("Configuration invalide:\n%v", err)
+	}
+	if err := handlers.Configure(cfg); err != nil {
+		log.Fatalf("Démarrage impossible: %v", err)
+	}
+	if cfg.Dev {
+		log.Printf("🛠️ Mode développement : templates relus depuis %s, fichiers statiques depuis %s", cfg.TemplatesDir, cfg.StaticDir)
+	}
+
+	http.HandleFunc("/static/", handlers.StaticHandler)
 	http.HandleFunc("/", handlers.HomeHandler)
 	http.HandleFunc("/artists", handlers.ArtistsHandler)
+	http.HandleFunc("/artists/export", handlers.ExportHandler)
 	http.HandleFunc("/artist/", handlers.ArtistDetailHandler)
+	http.HandleFunc("/artist/{id}/concerts.ics", handlers.ArtistCalendarHandler)
+	http.HandleFunc("/spotify/artist/{spotifyId}", handlers.ArtistPermalinkHandler)
+	http.HandleFunc("/location/{slug}/concerts.ics", handlers.LocationCalendarHandler)
 	http.HandleFunc("/search", handlers.SearchHandler)
 	http.HandleFunc("/suggestions", handlers.SuggestionsHandler)
 	http.HandleFunc("/gims", handlers.GimsHandler)
+	http.HandleFunc("/events", handlers.EventsHandler)
+
+	// API JSON versionnée et son contrat OpenAPI
+	for _, route := range handlers.APIRoutes {
+		http.HandleFunc(route.Pattern, route.Handler)
+	}
+	http.HandleFunc("/api/openapi.json", handlers.OpenAPIHandler)
+	http.HandleFunc("/api/", handlers.APINotFoundHandler)
+
+	// GraphQL (artistes, albums, titres, artistes similaires)
+	http.HandleFunc("/graphql", handlers.GraphQLHandler)
+	http.HandleFunc("/graphql/schema.graphql", handlers.GraphQLSchemaHandler)
+
+	// Flux Atom des nouvelles sorties, alimentés par un relevé périodique
+	http.HandleFunc("/feeds/releases.atom", handlers.ReleasesFeedHandler)
+	http.HandleFunc("/feeds/artist/{file}", handlers.ArtistFeedHandler)
+	http.HandleFunc("/feeds/genre/{file}", handlers.GenreFeedHandler)
+
+	// Métriques au format Prometheus et sondes de l'orchestrateur
+	http.Handle("/metrics", handlers.MetricsHandler)
+	http.HandleFunc("/healthz", handlers.HealthzHandler)
+	http.HandleFunc("/readyz", handlers.ReadyzHandler)
+
+	// Administration (désactivée sans adminToken) : état des caches, rafraîchissement, rechargement
+	http.HandleFunc("/admin", handlers.AdminHandler)
+	http.HandleFunc("/admin/refresh", handlers.AdminRefreshHandler)
+	http.HandleFunc("/admin/evict", handlers.AdminEvictHandler)
+	http.HandleFunc("/admin/reload", handlers.AdminReloadHandler)
+	handlers.SetConfigReloader(func() (config.Config, error) {
+		cfg, _, err := config.Load(os.Args[1:])
+		return cfg, err
+	})
+
+	// SIGINT/SIGTERM : arrêt propre (requêtes en cours terminées, relevés et rafraîchissements arrêtés, état sauvegardé)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	handlers.SetServerContext(ctx)
+
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		handlers.WatchReleases(ctx, cfg.ReleaseInterval.Duration)
+	}()
+
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handlers.WithRequestLog(handlers.WithLanguage(handlers.WithMetrics(http.DefaultServeMux))),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout.Duration,
+		ReadTimeout:       cfg.Server.ReadTimeout.Duration,
+		WriteTimeout:      cfg.Server.WriteTimeout.Duration,
+		IdleTimeout:       cfg.Server.IdleTimeout.Duration,
+	}
+	srv.RegisterOnShutdown(handlers.CloseEventStreams)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
 
-	port := ":8000"
-	log.Printf("🚀 Serveur démarré sur http://localhost%s", port)
-	log.Printf("🚀 Serveur également accessible sur http://[::1]%s", port)
+	if strings.HasPrefix(cfg.Addr, ":") {
+		log.Printf("🚀 Serveur démarré sur http://localhost%s", cfg.Addr)
+		log.Printf("🚀 Serveur également accessible sur http://[::1]%s", cfg.Addr)
+	} else {
+		log.Printf("🚀 Serveur démarré sur http://%s", cfg.Addr)
+	}
 
-	//suppression des messages inutiles
+	select {
+	case err := <-serveErr:
+		log.Fatalf("Erreur du serveur: %v", err)
+	case <-ctx.Done():
+	}
+	stop() // Un second signal interrompt immédiatement
+	log.Printf("Arrêt demandé, fin des requêtes en cours...")
 
-	log.Fatal(http.ListenAndServe(port, nil))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Requêtes interrompues à l'arrêt: %v", err)
+	}
+	select {
+	case <-watcherDone:
+	case <-shutdownCtx.Done():
+		log.Printf("Relevé des sorties interrompu à l'arrêt")
+	}
+	if !handlers.WaitBackground(shutdownCtx) {
+		log.Printf("Rafraîchissement du catalogue interrompu à l'arrêt")
+	}
+	if err := handlers.SaveState(); err != nil {
+		log.Printf("Erreur lors de la sauvegarde avant l'arrêt: %v", err)
+	}
+	log.Printf("👋 Serveur arrêté")
 }
@@ -0,0 +1,404 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"groupie-tracker-ng/api"
+	"groupie-tracker-ng/utils"
+)
+
+// Config est la configuration du serveur. Chaque réglage vient, par priorité croissante,
+// des valeurs par défaut, du fichier (--config ou GROUPIE_CONFIG, JSON ou TOML),
+// de l'environnement puis des options de la ligne de commande.
+type Config struct {
+	Addr            string   `json:"addr"`            // Adresse d'écoute (":8000", "127.0.0.1:8080")
+	Dev             bool     `json:"dev"`             // Mode développement : templates relus dès qu'ils changent
+	TemplatesDir    string   `json:"templatesDir"`    // Templates HTML sur disque (vide : embarqués)
+	StaticDir       string   `json:"staticDir"`       // Fichiers statiques sur disque (vide : embarqués)
+	ReleasesFile    string   `json:"releasesFile"`    // Registre des sorties déjà vues
+	ReleaseInterval Duration `json:"releaseInterval"` // Intervalle entre deux relevés des sorties
+	ConcertsFile    string   `json:"concertsFile"`    // Concerts des artistes, par ID Spotify (vide : aucun concert connu)
+	AdminToken      string   `json:"adminToken"`      // Jeton d'accès à /admin (vide : administration désactivée)
+	Server          Server   `json:"server"`
+	Spotify         Spotify  `json:"spotify"`
+	Ranking         Ranking  `json:"ranking"` // Classement des suggestions de recherche
+}
+
+// Server regroupe les délais du serveur HTTP
+type Server struct {
+	ReadHeaderTimeout Duration `json:"readHeaderTimeout"` // Lecture des en-têtes d'une requête
+	ReadTimeout       Duration `json:"readTimeout"`       // Lecture de la requête complète
+	WriteTimeout      Duration `json:"writeTimeout"`      // Réponse (hors flux /events) ; un catalogue froid prend des dizaines de secondes
+	IdleTimeout       Duration `json:"idleTimeout"`       // Connexion keep-alive inactive
+	ShutdownTimeout   Duration `json:"shutdownTimeout"`   // Attente des requêtes en cours à l'arrêt
+}
+
+// Spotify regroupe les réglages du client Spotify
+type Spotify struct {
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"` // Jamais sur la ligne de commande (visible dans ps)
+	Market       string   `json:"market"`       // Code pays ISO 3166-1 des catalogues ("FR")
+	Timeout      Duration `json:"timeout"`      // Délai maximum d'un appel
+	CacheTTL     Duration `json:"cacheTTL"`     // Validité du catalogue en cache
+	Concurrency  int      `json:"concurrency"`  // Appels simultanés d'un même chargement
+}
+
+// Default retourne la configuration par défaut
+func Default() Config {
+	return Config{
+		Addr:            ":8000",
+		ReleasesFile:    "data/releases.json",
+		ReleaseInterval: Duration{time.Hour},
+		Server: Server{
+			ReadHeaderTimeout: Duration{5 * time.Second},
+			ReadTimeout:       Duration{15 * time.Second},
+			WriteTimeout:      Duration{90 * time.Second},
+			IdleTimeout:       Duration{2 * time.Minute},
+			ShutdownTimeout:   Duration{20 * time.Second},
+		},
+		Spotify: Spotify{
+			Market:      api.DefaultMarket,
+			Timeout:     Duration{api.DefaultTimeout},
+			CacheTTL:    Duration{api.DefaultCacheTTL},
+			Concurrency: api.DefaultConcurrency,
+		},
+		Ranking: Ranking{utils.DefaultRankingWeights},
+	}
+}
+
+// SpotifySettings retourne les réglages à appliquer au client Spotify
+func (c Config) SpotifySettings() api.Settings {
+	return api.Settings{
+		ClientID:     c.Spotify.ClientID,
+		ClientSecret: c.Spotify.ClientSecret,
+		Market:       c.Spotify.Market,
+		Timeout:      c.Spotify.Timeout.Duration,
+		CacheTTL:     c.Spotify.CacheTTL.Duration,
+		Concurrency:  c.Spotify.Concurrency,
+	}
+}
+
+// Load construit la configuration à partir des arguments (sans le nom du programme)
+// et de l'environnement. printConfig indique que --print-config a été demandé.
+func Load(args []string) (cfg Config, printConfig bool, err error) {
+	// Premier passage : seulement pour connaître le fichier de configuration
+	var path string
+	var scratch Config
+	if err := newFlagSet(&scratch, &path, &printConfig).Parse(args); err != nil {
+		return cfg, false, err
+	}
+	if path == "" {
+		path = os.Getenv("GROUPIE_CONFIG")
+	}
+
+	cfg = Default()
+	if path != "" {
+		if err := loadFile(&cfg, path); err != nil {
+			return cfg, false, err
+		}
+	}
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, false, err
+	}
+	// Second passage : les options données remplacent le fichier et l'environnement
+	if err := newFlagSet(&cfg, &path, &printConfig).Parse(args); err != nil {
+		return cfg, false, err
+	}
+	if cfg.Dev {
+		// Le mode développement relit les sources, pas les fichiers embarqués
+		if cfg.TemplatesDir == "" {
+			cfg.TemplatesDir = "templates"
+		}
+		if cfg.StaticDir == "" {
+			cfg.StaticDir = "static"
+		}
+	}
+	return cfg, printConfig, cfg.Validate()
+}
+
+// newFlagSet déclare les options de la ligne de commande, liées aux champs de cfg
+func newFlagSet(cfg *Config, path *string, printConfig *bool) *flag.FlagSet {
+	fs := flag.NewFlagSet("groupie-tracker", flag.ContinueOnError)
+	fs.StringVar(path, "config", *path, "fichier de configuration (.json ou .toml)")
+	fs.BoolVar(printConfig, "print-config", *printConfig, "afficher la configuration effective et quitter")
+	fs.StringVar(&cfg.Addr, "addr", cfg.Addr, "adresse d'écoute")
+	fs.BoolVar(&cfg.Dev, "dev", cfg.Dev, "mode développement (templates relus depuis le disque)")
+	fs.StringVar(&cfg.TemplatesDir, "templates", cfg.TemplatesDir, "dossier des templates (vide : embarqués)")
+	fs.StringVar(&cfg.StaticDir, "static", cfg.StaticDir, "dossier des fichiers statiques (vide : embarqués)")
+	fs.StringVar(&cfg.ReleasesFile, "releases-file", cfg.ReleasesFile, "registre des sorties")
+	fs.Var(&cfg.ReleaseInterval, "release-interval", "intervalle entre deux relevés des sorties")
+	fs.StringVar(&cfg.ConcertsFile, "concerts-file", cfg.ConcertsFile, "fichier des concerts (vide : aucun concert connu)")
+	fs.Var(&cfg.Server.ReadHeaderTimeout, "read-header-timeout", "délai de lecture des en-têtes")
+	fs.Var(&cfg.Server.ReadTimeout, "read-timeout", "délai de lecture d'une requête")
+	fs.Var(&cfg.Server.WriteTimeout, "write-timeout", "délai d'écriture d'une réponse")
+	fs.Var(&cfg.Server.IdleTimeout, "idle-timeout", "délai d'inactivité d'une connexion")
+	fs.Var(&cfg.Server.ShutdownTimeout, "shutdown-timeout", "attente des requêtes en cours à l'arrêt")
+	fs.StringVar(&cfg.Spotify.Market, "market", cfg.Spotify.Market, "marché Spotify (code pays)")
+	fs.Var(&cfg.Spotify.Timeout, "spotify-timeout", "délai maximum d'un appel Spotify")
+	fs.Var(&cfg.Spotify.CacheTTL, "cache-ttl", "validité du catalogue en cache")
+	fs.IntVar(&cfg.Spotify.Concurrency, "concurrency", cfg.Spotify.Concurrency, "appels Spotify simultanés")
+	fs.Var(&cfg.Ranking, "ranking", "pondérations du classement des suggestions (ex. \"popularity=30,followers=5\")")
+	return fs
+}
+
+// loadFile lit un fichier JSON ou TOML (selon l'extension) ; les clés inconnues sont refusées
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("lecture de la configuration : %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".toml":
+		values, err := parseTOML(string(data))
+		if err != nil {
+			return fmt.Errorf("%s : %w", path, err)
+		}
+		// Même décodage que le JSON : les clés TOML sont les noms JSON des champs
+		if data, err = json.Marshal(values); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s : format de configuration inconnu (.json ou .toml)", path)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("%s : %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv applique les variables d'environnement définies
+func loadEnv(cfg *Config) error {
+	strs := map[string]*string{
+		"GROUPIE_ADDR":          &cfg.Addr,
+		"GROUPIE_TEMPLATES":     &cfg.TemplatesDir,
+		"GROUPIE_STATIC":        &cfg.StaticDir,
+		"GROUPIE_RELEASES_FILE": &cfg.ReleasesFile,
+		"GROUPIE_CONCERTS_FILE": &cfg.ConcertsFile,
+		"GROUPIE_ADMIN_TOKEN":   &cfg.AdminToken,
+		"SPOTIFY_CLIENT_ID":     &cfg.Spotify.ClientID,
+		"SPOTIFY_CLIENT_SECRET": &cfg.Spotify.ClientSecret,
+		"SPOTIFY_MARKET":        &cfg.Spotify.Market,
+	}
+	for name, field := range strs {
+		if value, ok := os.LookupEnv(name); ok {
+			*field = value
+		}
+	}
+
+	durations := map[string]*Duration{
+		"GROUPIE_RELEASE_INTERVAL":    &cfg.ReleaseInterval,
+		"GROUPIE_READ_HEADER_TIMEOUT": &cfg.Server.ReadHeaderTimeout,
+		"GROUPIE_READ_TIMEOUT":        &cfg.Server.ReadTimeout,
+		"GROUPIE_WRITE_TIMEOUT":       &cfg.Server.WriteTimeout,
+		"GROUPIE_IDLE_TIMEOUT":        &cfg.Server.IdleTimeout,
+		"GROUPIE_SHUTDOWN_TIMEOUT":    &cfg.Server.ShutdownTimeout,
+		"SPOTIFY_TIMEOUT":             &cfg.Spotify.Timeout,
+		"SPOTIFY_CACHE_TTL":           &cfg.Spotify.CacheTTL,
+	}
+	for name, field := range durations {
+		if value, ok := os.LookupEnv(name); ok {
+			if err := field.Set(value); err != nil {
+				return fmt.Errorf("%s : %w", name, err)
+			}
+		}
+	}
+
+	if value, ok := os.LookupEnv("GROUPIE_RANKING"); ok {
+		if err := cfg.Ranking.Set(value); err != nil {
+			return fmt.Errorf("GROUPIE_RANKING : %w", err)
+		}
+	}
+
+	if value, ok := os.LookupEnv("SPOTIFY_CONCURRENCY"); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("SPOTIFY_CONCURRENCY : nombre entier attendu (%q)", value)
+		}
+		cfg.Spotify.Concurrency = n
+	}
+
+	if value, ok := os.LookupEnv("GROUPIE_DEV"); ok {
+		dev, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("GROUPIE_DEV : booléen attendu (%q)", value)
+		}
+		cfg.Dev = dev
+	}
+	return nil
+}
+
+// Validate vérifie la cohérence de la configuration et retourne toutes les erreurs à la fois
+func (c Config) Validate() error {
+	var errs []error
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("addr : adresse d'écoute invalide %q (ex. \":8000\")", c.Addr))
+	}
+	for _, dir := range []struct{ name, path string }{{"templatesDir", c.TemplatesDir}, {"staticDir", c.StaticDir}} {
+		if dir.path == "" {
+			continue // Fichiers embarqués
+		}
+		if info, err := os.Stat(dir.path); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("%s : dossier introuvable %q", dir.name, dir.path))
+		}
+	}
+	if c.ReleasesFile == "" {
+		errs = append(errs, errors.New("releasesFile : chemin vide"))
+	}
+	if c.ConcertsFile != "" {
+		if info, err := os.Stat(c.ConcertsFile); err != nil || info.IsDir() {
+			errs = append(errs, fmt.Errorf("concertsFile : fichier introuvable %q", c.ConcertsFile))
+		}
+	}
+	if c.ReleaseInterval.Duration < time.Minute {
+		errs = append(errs, errors.New("releaseInterval : une minute au moins"))
+	}
+	if c.AdminToken != "" && len(c.AdminToken) < 16 {
+		errs = append(errs, errors.New("adminToken : 16 caractères au moins"))
+	}
+	for _, d := range []struct {
+		name  string
+		value Duration
+	}{
+		{"server.readHeaderTimeout", c.Server.ReadHeaderTimeout},
+		{"server.readTimeout", c.Server.ReadTimeout},
+		{"server.writeTimeout", c.Server.WriteTimeout},
+		{"server.idleTimeout", c.Server.IdleTimeout},
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+	} {
+		if d.value.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s : durée positive attendue", d.name))
+		}
+	}
+	if len(c.Spotify.Market) != 2 || strings.ToUpper(c.Spotify.Market) != c.Spotify.Market {
+		errs = append(errs, fmt.Errorf("spotify.market : code pays en deux majuscules attendu (%q)", c.Spotify.Market))
+	}
+	if c.Spotify.Timeout.Duration <= 0 {
+		errs = append(errs, errors.New("spotify.timeout : durée positive attendue"))
+	}
+	if c.Spotify.CacheTTL.Duration <= 0 {
+		errs = append(errs, errors.New("spotify.cacheTTL : durée positive attendue"))
+	}
+	if c.Spotify.Concurrency < 1 || c.Spotify.Concurrency > 32 {
+		errs = append(errs, fmt.Errorf("spotify.concurrency : entre 1 et 32 (%d)", c.Spotify.Concurrency))
+	}
+	for _, w := range c.Ranking.weights() {
+		if *w.value < 0 {
+			errs = append(errs, fmt.Errorf("ranking.%s : valeur positive ou nulle attendue (%g)", w.name, *w.value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Print écrit la configuration effective en JSON, secrets masqués
+func Print(w io.Writer, c Config) error {
+	if c.Spotify.ClientSecret != "" {
+		c.Spotify.ClientSecret = "********"
+	}
+	if c.AdminToken != "" {
+		c.AdminToken = "********"
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// Duration est une durée lue et écrite sous forme de texte ("90s", "5m", "1h")
+type Duration struct {
+	time.Duration
+}
+
+// Set lit une durée (flag.Value)
+func (d *Duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("durée invalide %q (ex. \"90s\", \"5m\")", s)
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalText écrit la durée ("5m0s")
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText lit la durée depuis le fichier de configuration
+func (d *Duration) UnmarshalText(text []byte) error {
+	return d.Set(string(text))
+}
+
+// Ranking regroupe les pondérations du classement des suggestions. Dans l'environnement et sur la
+// ligne de commande, seules les pondérations données changent : "popularity=30,followers=5".
+type Ranking struct {
+	utils.RankingWeights
+}
+
+// weights retourne les pondérations sous leur nom de fichier de configuration, dans l'ordre du type
+func (r *Ranking) weights() []struct {
+	name  string
+	value *float64
+} {
+	return []struct {
+		name  string
+		value *float64
+	}{
+		{"exact", &r.Exact},
+		{"prefix", &r.Prefix},
+		{"wordPrefix", &r.WordPrefix},
+		{"substring", &r.Substring},
+		{"fuzzy", &r.Fuzzy},
+		{"fuzzyPenalty", &r.FuzzyPenalty},
+		{"popularity", &r.Popularity},
+		{"followers", &r.Followers},
+	}
+}
+
+// Set lit une liste "nom=valeur" séparée par des virgules (flag.Value)
+func (r *Ranking) Set(s string) error {
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("pondération %q : nom=valeur attendu", pair)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("pondération %q : nombre attendu", pair)
+		}
+		found := false
+		for _, w := range r.weights() {
+			if strings.EqualFold(w.name, strings.TrimSpace(name)) {
+				*w.value = v
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("pondération inconnue %q", name)
+		}
+	}
+	return nil
+}
+
+// String écrit toutes les pondérations ("exact=100,prefix=80,...")
+func (r *Ranking) String() string {
+	pairs := make([]string, 0, 8)
+	for _, w := range r.weights() {
+		pairs = append(pairs, w.name+"="+strconv.FormatFloat(*w.value, 'g', -1, 64))
+	}
+	return strings.Join(pairs, ",")
+}
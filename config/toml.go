@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML lit le sous-ensemble de TOML utile à la configuration : commentaires, tables
+// d'un niveau ([spotify]), et valeurs chaînes ("..." ou '...'), entiers, décimaux et booléens.
+func parseTOML(src string) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	table := root
+
+	for n, line := range strings.Split(src, "\n") {
+		lineNo := n + 1
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, rest, ok := strings.Cut(line[1:], "]")
+			name = strings.TrimSpace(name)
+			if !ok || !isBareKey(name) || !isComment(rest) {
+				return nil, fmt.Errorf("ligne %d : table invalide %q", lineNo, line)
+			}
+			if _, exists := root[name]; exists {
+				return nil, fmt.Errorf("ligne %d : table [%s] déjà définie", lineNo, name)
+			}
+			table = map[string]interface{}{}
+			root[name] = table
+			continue
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isBareKey(key) {
+			return nil, fmt.Errorf("ligne %d : « clé = valeur » attendu", lineNo)
+		}
+		if _, exists := table[key]; exists {
+			return nil, fmt.Errorf("ligne %d : clé %q déjà définie", lineNo, key)
+		}
+		value, err := parseTOMLValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("ligne %d : %w", lineNo, err)
+		}
+		table[key] = value
+	}
+	return root, nil
+}
+
+// parseTOMLValue lit une valeur suivie d'un éventuel commentaire
+func parseTOMLValue(raw string) (interface{}, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("valeur manquante")
+	}
+
+	switch raw[0] {
+	case '"':
+		var sb strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			switch {
+			case c == '"':
+				if !isComment(raw[i+1:]) {
+					return nil, fmt.Errorf("texte inattendu après la chaîne")
+				}
+				return sb.String(), nil
+			case c == '\\' && i+1 < len(raw):
+				i++
+				switch raw[i] {
+				case '"', '\\':
+					sb.WriteByte(raw[i])
+				case 'n':
+					sb.WriteByte('\n')
+				case 't':
+					sb.WriteByte('\t')
+				default:
+					return nil, fmt.Errorf("échappement \\%c non pris en charge", raw[i])
+				}
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return nil, fmt.Errorf("chaîne non terminée")
+
+	case '\'':
+		body, rest, ok := strings.Cut(raw[1:], "'")
+		if !ok {
+			return nil, fmt.Errorf("chaîne non terminée")
+		}
+		if !isComment(rest) {
+			return nil, fmt.Errorf("texte inattendu après la chaîne")
+		}
+		return body, nil
+	}
+
+	value, _, _ := strings.Cut(raw, "#")
+	value = strings.TrimSpace(value)
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(strings.ReplaceAll(value, "_", ""), 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("valeur invalide %q (chaîne, nombre ou booléen)", value)
+}
+
+// isBareKey indique si name est une clé TOML sans guillemets
+func isBareKey(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isComment indique si rest (fin de ligne) est vide ou un commentaire
+func isComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || rest[0] == '#'
+}
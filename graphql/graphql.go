@@ -0,0 +1,826 @@
+// Package graphql exécute des requêtes GraphQL en lecture seule sur un schéma déclaré en Go :
+// analyse (Parse), validation contre le schéma, puis résolution niveau par niveau (Resolver).
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// SCHÉMA ET EXÉCUTION
+// ============================================
+
+// Resolver résout un champ pour tous les objets parents d'un même niveau en un seul appel,
+// ce qui permet de regrouper les accès aux données (pas de N+1) ; il retourne une valeur par parent.
+// Une erreur accompagnée d'autant de valeurs que de parents est partielle : elle est signalée
+// et les valeurs conservées. Sans valeurs, le champ vaut null pour tous les parents.
+type Resolver func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error)
+
+// Arg est un argument de champ
+type Arg struct {
+	Name        string
+	Type        string // "Int", "String!", "[String]"...
+	Description string
+}
+
+// Field est un champ d'un type objet
+type Field struct {
+	Type        string // Type scalaire, objet du schéma ou liste ("[Album]")
+	Args        []Arg
+	Description string
+	Resolve     Resolver // nil : champ de la struct parente portant ce nom JSON
+}
+
+// Object est un type objet du schéma
+type Object struct {
+	Name        string
+	Description string
+	Fields      map[string]*Field
+}
+
+// Schema est un schéma GraphQL exécutable
+type Schema struct {
+	Query  *Object
+	Types  map[string]*Object
+	Limits Limits
+}
+
+// Limits borne les requêtes acceptées ; une requête qui les dépasse est refusée à la validation.
+// Zéro : pas de limite.
+type Limits struct {
+	MaxDepth  int // Niveaux de champs imbriqués (les champs de Query sont au niveau 1)
+	MaxFields int // Champs sélectionnés, chaque utilisation d'un fragment comptant ses champs
+}
+
+// scalars sont les types scalaires pris en charge
+var scalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// NewSchema assemble un schéma ; un type de champ ou d'argument inconnu est une erreur
+// de programmation et provoque un panic (le schéma est construit au démarrage)
+func NewSchema(query *Object, types ...*Object) *Schema {
+	s := &Schema{Query: query, Types: map[string]*Object{query.Name: query}}
+	for _, t := range types {
+		s.Types[t.Name] = t
+	}
+	for _, t := range s.Types {
+		for name, f := range t.Fields {
+			if named := namedType(f.Type); !scalars[named] && s.Types[named] == nil {
+				panic(fmt.Sprintf("graphql: type %q inconnu pour %s.%s", named, t.Name, name))
+			}
+			for _, arg := range f.Args {
+				if !scalars[namedType(arg.Type)] {
+					panic(fmt.Sprintf("graphql: type d'argument %q non scalaire pour %s.%s(%s)", arg.Type, t.Name, name, arg.Name))
+				}
+			}
+		}
+	}
+	return s
+}
+
+// WithLimits fixe les limites de taille des requêtes et retourne le schéma
+func (s *Schema) WithLimits(limits Limits) *Schema {
+	s.Limits = limits
+	return s
+}
+
+// Request est une requête GraphQL reçue (GET ou corps JSON)
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response est la réponse GraphQL ; Data est absent si la requête n'a pas pu être exécutée
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Execute analyse, valide puis exécute une requête
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	if strings.TrimSpace(req.Query) == "" {
+		return &Response{Errors: []*Error{{Message: "paramètre \"query\" manquant"}}}
+	}
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}
+	}
+	if errs := s.validate(doc, op); len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+
+	e := &execution{ctx: ctx, schema: s, doc: doc}
+	if e.vars, err = coerceVariables(op, req.Variables); err != nil {
+		return &Response{Errors: []*Error{asError(err)}}
+	}
+
+	results := e.executeSet(s.Query, []interface{}{struct{}{}}, [][]interface{}{nil}, op.Selections)
+	return &Response{Data: results[0], Errors: e.errors}
+}
+
+// asError convertit une erreur quelconque en erreur GraphQL
+func asError(err error) *Error {
+	if ge, ok := err.(*Error); ok {
+		return ge
+	}
+	return &Error{Message: err.Error()}
+}
+
+// selectOperation choisit l'opération à exécuter (par nom s'il y en a plusieurs)
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, &Error{Message: "plusieurs opérations : \"operationName\" est requis"}
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("opération %q introuvable", name)}
+}
+
+// ============================================
+// Validation (avant toute résolution)
+// ============================================
+
+// validate vérifie l'opération contre le schéma : champs, arguments, sélections, fragments et variables
+func (s *Schema) validate(doc *Document, op *Operation) []*Error {
+	if op.Type != "query" {
+		return []*Error{errorAt(op.Location, "opération %q non prise en charge (lecture seule)", op.Type)}
+	}
+
+	v := &validator{schema: s, doc: doc, vars: map[string]bool{}, visiting: map[string]bool{}, depth: 1}
+	for _, variable := range op.Variables {
+		if v.vars[variable.Name] {
+			v.errorf(op.Location, "variable $%s déclarée plusieurs fois", variable.Name)
+		}
+		v.vars[variable.Name] = true
+		if !scalars[namedType(variable.Type)] {
+			v.errorf(op.Location, "type %q non pris en charge pour la variable $%s", variable.Type, variable.Name)
+		}
+	}
+	v.selections(s.Query, op.Selections)
+	return v.errors
+}
+
+type validator struct {
+	schema   *Schema
+	doc      *Document
+	vars     map[string]bool
+	visiting map[string]bool // Fragments en cours de visite (détection des cycles)
+	depth    int             // Niveau des champs en cours de validation
+	fields   int             // Champs rencontrés jusqu'ici
+	stopped  bool            // Limite dépassée : inutile de poursuivre
+	errors   []*Error
+}
+
+func (v *validator) errorf(loc Location, format string, args ...interface{}) {
+	v.errors = append(v.errors, errorAt(loc, format, args...))
+}
+
+func (v *validator) selections(obj *Object, sels []Selection) {
+	for _, sel := range sels {
+		if v.stopped {
+			return
+		}
+		for _, d := range sel.Directives {
+			if d.Name != "skip" && d.Name != "include" {
+				v.errorf(sel.Location, "directive @%s inconnue", d.Name)
+			} else if _, ok := d.Args["if"]; !ok {
+				v.errorf(sel.Location, "argument \"if\" requis pour @%s", d.Name)
+			}
+			v.values(sel.Location, d.Args)
+		}
+
+		switch {
+		case sel.Spread != "":
+			frag, ok := v.doc.Fragments[sel.Spread]
+			if !ok {
+				v.errorf(sel.Location, "fragment %q inconnu", sel.Spread)
+				continue
+			}
+			if v.visiting[frag.Name] {
+				v.errorf(sel.Location, "le fragment %q s'inclut lui-même", frag.Name)
+				continue
+			}
+			if v.typeCondition(sel.Location, frag.On, obj) {
+				v.visiting[frag.Name] = true
+				v.selections(obj, frag.Selections)
+				delete(v.visiting, frag.Name)
+			}
+		case sel.Inline:
+			if sel.On == "" || v.typeCondition(sel.Location, sel.On, obj) {
+				v.selections(obj, sel.Selections)
+			}
+		default:
+			v.field(obj, sel)
+		}
+	}
+}
+
+// typeCondition vérifie qu'un fragment porte sur le type courant (le schéma n'a que des types objets)
+func (v *validator) typeCondition(loc Location, on string, obj *Object) bool {
+	if _, ok := v.schema.Types[on]; !ok {
+		v.errorf(loc, "type %q inconnu", on)
+		return false
+	}
+	if on != obj.Name {
+		v.errorf(loc, "un fragment sur %q ne peut pas s'appliquer au type %q", on, obj.Name)
+		return false
+	}
+	return true
+}
+
+func (v *validator) field(obj *Object, sel Selection) {
+	v.fields++
+	if limit := v.schema.Limits.MaxFields; limit > 0 && v.fields > limit {
+		v.errorf(sel.Location, "requête trop complexe : %d champs au plus", limit)
+		v.stopped = true
+		return
+	}
+	if sel.Name == "__typename" {
+		if len(sel.Selections) > 0 || len(sel.Args) > 0 {
+			v.errorf(sel.Location, "__typename n'accepte ni arguments ni sous-sélection")
+		}
+		return
+	}
+	def, ok := obj.Fields[sel.Name]
+	if !ok {
+		v.errorf(sel.Location, "champ %q inconnu sur le type %q", sel.Name, obj.Name)
+		return
+	}
+
+	known := map[string]bool{}
+	for _, arg := range def.Args {
+		known[arg.Name] = true
+		if _, given := sel.Args[arg.Name]; !given && strings.HasSuffix(arg.Type, "!") {
+			v.errorf(sel.Location, "argument %q requis pour le champ %q", arg.Name, sel.Name)
+		}
+	}
+	for name := range sel.Args {
+		if !known[name] {
+			v.errorf(sel.Location, "argument %q inconnu pour le champ %q", name, sel.Name)
+		}
+	}
+	v.values(sel.Location, sel.Args)
+
+	if child, isObject := v.schema.Types[namedType(def.Type)]; isObject {
+		if len(sel.Selections) == 0 {
+			v.errorf(sel.Location, "le champ %q de type %s requiert une sous-sélection", sel.Name, def.Type)
+			return
+		}
+		if limit := v.schema.Limits.MaxDepth; limit > 0 && v.depth >= limit {
+			v.errorf(sel.Location, "requête trop profonde : %d niveaux de champs au plus", limit)
+			v.stopped = true
+			return
+		}
+		v.depth++
+		v.selections(child, sel.Selections)
+		v.depth--
+	} else if len(sel.Selections) > 0 {
+		v.errorf(sel.Location, "le champ %q de type %s n'accepte pas de sous-sélection", sel.Name, def.Type)
+	}
+}
+
+// values vérifie que les variables utilisées sont déclarées
+func (v *validator) values(loc Location, args map[string]interface{}) {
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch val := value.(type) {
+		case VarRef:
+			if !v.vars[string(val)] {
+				v.errorf(loc, "variable $%s non déclarée", val)
+			}
+		case []interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case map[string]interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		}
+	}
+	for _, value := range args {
+		walk(value)
+	}
+}
+
+// ============================================
+// Exécution par niveaux : chaque champ est résolu pour tous ses parents à la fois
+// ============================================
+
+type execution struct {
+	ctx    context.Context
+	schema *Schema
+	doc    *Document
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+// executeSet résout une sélection pour des parents du même type ; retourne un objet par parent
+func (e *execution) executeSet(obj *Object, parents []interface{}, paths [][]interface{}, sels []Selection) []interface{} {
+	results := make([]*resultMap, len(parents))
+	for i := range results {
+		results[i] = newResultMap()
+	}
+
+	for _, group := range e.collectFields(obj, sels, nil, map[string]int{}) {
+		sel := group[0]
+		key := sel.ResponseKey()
+		if sel.Name == "__typename" {
+			for _, r := range results {
+				r.set(key, obj.Name)
+			}
+			continue
+		}
+
+		def := obj.Fields[sel.Name]
+		fieldPaths := make([][]interface{}, len(paths))
+		for i, p := range paths {
+			fieldPaths[i] = appendPath(p, key)
+		}
+
+		values, err := e.resolve(obj, def, sel, parents)
+		if err != nil {
+			e.errors = append(e.errors, &Error{Message: err.Error(), Locations: []Location{sel.Location}, Path: fieldPaths[0]})
+			if len(values) != len(parents) {
+				for _, r := range results {
+					r.set(key, nil)
+				}
+				continue
+			}
+		}
+
+		var subSelections []Selection
+		for _, s := range group {
+			subSelections = append(subSelections, s.Selections...)
+		}
+		completed := e.completeAll(def.Type, values, fieldPaths, subSelections)
+		for i, r := range results {
+			r.set(key, completed[i])
+		}
+	}
+
+	out := make([]interface{}, len(results))
+	for i, r := range results {
+		out[i] = r
+	}
+	return out
+}
+
+// resolve prépare les arguments puis appelle le résolveur (les panics deviennent des erreurs)
+func (e *execution) resolve(obj *Object, def *Field, sel Selection, parents []interface{}) (values []interface{}, err error) {
+	args, err := e.coerceArgs(def, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := def.Resolve
+	if resolver == nil {
+		resolver = structField(sel.Name)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			values, err = nil, fmt.Errorf("erreur interne lors de la résolution de %s.%s: %v", obj.Name, sel.Name, r)
+		}
+	}()
+
+	values, err = resolver(e.ctx, parents, args)
+	if err == nil && len(values) != len(parents) {
+		return nil, fmt.Errorf("résolveur %s.%s : %d valeurs pour %d objets", obj.Name, sel.Name, len(values), len(parents))
+	}
+	return values, err
+}
+
+// completeAll met en forme les valeurs résolues selon le type du champ. Les listes sont aplaties
+// pour que les sous-champs soient résolus en une fois pour tous leurs éléments.
+func (e *execution) completeAll(typeRef string, values []interface{}, paths [][]interface{}, sels []Selection) []interface{} {
+	out := make([]interface{}, len(values))
+	t := strings.TrimSuffix(typeRef, "!")
+
+	if strings.HasPrefix(t, "[") {
+		var items []interface{}
+		var itemPaths [][]interface{}
+		counts := make([]int, len(values))
+		for i, value := range values {
+			list, ok := asList(value)
+			if !ok {
+				counts[i] = -1
+				continue
+			}
+			counts[i] = len(list)
+			for j, item := range list {
+				items = append(items, item)
+				itemPaths = append(itemPaths, appendPath(paths[i], j))
+			}
+		}
+
+		completed := e.completeAll(t[1:len(t)-1], items, itemPaths, sels)
+		k := 0
+		for i, n := range counts {
+			if n < 0 {
+				continue // null
+			}
+			out[i] = completed[k : k+n : k+n]
+			k += n
+		}
+		return out
+	}
+
+	if obj, ok := e.schema.Types[t]; ok {
+		var parents []interface{}
+		var parentPaths [][]interface{}
+		var positions []int
+		for i, value := range values {
+			if !isNull(value) {
+				parents = append(parents, value)
+				parentPaths = append(parentPaths, paths[i])
+				positions = append(positions, i)
+			}
+		}
+		if len(parents) == 0 {
+			return out
+		}
+		for j, result := range e.executeSet(obj, parents, parentPaths, sels) {
+			out[positions[j]] = result
+		}
+		return out
+	}
+
+	for i, value := range values {
+		if !isNull(value) {
+			out[i] = value
+		}
+	}
+	return out
+}
+
+// collectFields regroupe les champs par clé de réponse (fragments et directives appliqués),
+// dans l'ordre de la requête
+func (e *execution) collectFields(obj *Object, sels []Selection, groups [][]Selection, index map[string]int) [][]Selection {
+	for _, sel := range sels {
+		if !e.included(sel.Directives) {
+			continue
+		}
+		switch {
+		case sel.Spread != "":
+			if frag := e.doc.Fragments[sel.Spread]; frag.On == obj.Name {
+				groups = e.collectFields(obj, frag.Selections, groups, index)
+			}
+		case sel.Inline:
+			if sel.On == "" || sel.On == obj.Name {
+				groups = e.collectFields(obj, sel.Selections, groups, index)
+			}
+		default:
+			key := sel.ResponseKey()
+			if i, ok := index[key]; ok {
+				groups[i] = append(groups[i], sel)
+			} else {
+				index[key] = len(groups)
+				groups = append(groups, []Selection{sel})
+			}
+		}
+	}
+	return groups
+}
+
+// included applique @skip(if:) et @include(if:)
+func (e *execution) included(directives []Directive) bool {
+	for _, d := range directives {
+		cond, _ := e.substitute(d.Args["if"]).(bool)
+		if (d.Name == "skip" && cond) || (d.Name == "include" && !cond) {
+			return false
+		}
+	}
+	return true
+}
+
+// coerceArgs remplace les variables et convertit les arguments selon leur type déclaré
+func (e *execution) coerceArgs(def *Field, sel Selection) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(def.Args))
+	for _, arg := range def.Args {
+		raw, ok := sel.Args[arg.Name]
+		if ref, isVar := raw.(VarRef); isVar {
+			raw, ok = e.vars[string(ref)]
+		}
+		if !ok {
+			if strings.HasSuffix(arg.Type, "!") {
+				return nil, fmt.Errorf("argument %q requis", arg.Name)
+			}
+			continue
+		}
+		value, err := coerceInput(e.substitute(raw), arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q : %w", arg.Name, err)
+		}
+		args[arg.Name] = value
+	}
+	return args, nil
+}
+
+// substitute remplace les références de variables dans une valeur
+func (e *execution) substitute(value interface{}) interface{} {
+	switch v := value.(type) {
+	case VarRef:
+		return e.vars[string(v)]
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = e.substitute(item)
+		}
+		return out
+	}
+	return value
+}
+
+// coerceVariables applique les valeurs par défaut et convertit les variables fournies
+func coerceVariables(op *Operation, given map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(op.Variables))
+	for _, v := range op.Variables {
+		value, ok := given[v.Name]
+		if !ok {
+			if !v.HasDefault {
+				if strings.HasSuffix(v.Type, "!") {
+					return nil, fmt.Errorf("variable $%s requise", v.Name)
+				}
+				continue
+			}
+			value = v.Default
+		}
+		coerced, err := coerceInput(value, v.Type)
+		if err != nil {
+			return nil, fmt.Errorf("variable $%s : %w", v.Name, err)
+		}
+		vars[v.Name] = coerced
+	}
+	return vars, nil
+}
+
+// coerceInput convertit une valeur d'entrée (littéral ou JSON) vers un type scalaire ou liste
+func coerceInput(value interface{}, typeRef string) (interface{}, error) {
+	nonNull := strings.HasSuffix(typeRef, "!")
+	t := strings.TrimSuffix(typeRef, "!")
+	if value == nil {
+		if nonNull {
+			return nil, fmt.Errorf("valeur null interdite pour le type %s", typeRef)
+		}
+		return nil, nil
+	}
+
+	if strings.HasPrefix(t, "[") {
+		elem := t[1 : len(t)-1]
+		list, ok := value.([]interface{})
+		if !ok {
+			list = []interface{}{value} // Une valeur seule vaut une liste d'un élément
+		}
+		out := make([]interface{}, len(list))
+		for i, item := range list {
+			coerced, err := coerceInput(item, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	}
+
+	switch t {
+	case "Int":
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64: // Nombres des variables JSON
+			if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+				return int(v), nil
+			}
+		}
+	case "Float":
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case "String":
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case "ID":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int:
+			return strconv.Itoa(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return strconv.FormatInt(int64(v), 10), nil
+			}
+		}
+	case "Boolean":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("valeur %v invalide pour le type %s", value, typeRef)
+}
+
+// ============================================
+// Outils
+// ============================================
+
+// namedType retire listes et non-null d'une référence de type ("[Album!]!" -> "Album")
+func namedType(typeRef string) string {
+	return strings.Trim(typeRef, "[]!")
+}
+
+// asList retourne les éléments d'une valeur liste (slice Go) ; false si la valeur est null
+func asList(value interface{}) ([]interface{}, bool) {
+	if list, ok := value.([]interface{}); ok {
+		return list, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return nil, false
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// isNull indique si une valeur vaut null (nil, ou pointeur, map ou slice nil)
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// appendPath copie un chemin de réponse en y ajoutant un élément
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	out := make([]interface{}, len(path)+1)
+	copy(out, path)
+	out[len(path)] = elem
+	return out
+}
+
+// structField est le résolveur par défaut : il lit le champ de même nom JSON
+// dans chaque parent (struct, pointeur de struct ou map)
+func structField(name string) Resolver {
+	return func(_ context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+		values := make([]interface{}, len(parents))
+		for i, parent := range parents {
+			rv := reflect.ValueOf(parent)
+			for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+				rv = rv.Elem()
+			}
+			switch rv.Kind() {
+			case reflect.Struct:
+				if field, ok := jsonFieldByName(rv, name); ok {
+					values[i] = field.Interface()
+				}
+			case reflect.Map:
+				if v := rv.MapIndex(reflect.ValueOf(name)); v.IsValid() {
+					values[i] = v.Interface()
+				}
+			}
+		}
+		return values, nil
+	}
+}
+
+// jsonFieldByName cherche le champ dont le nom JSON est name (structs embarquées comprises)
+func jsonFieldByName(rv reflect.Value, name string) (reflect.Value, bool) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tagName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if sf.Anonymous && tagName == "" && sf.Type.Kind() == reflect.Struct {
+			if field, ok := jsonFieldByName(rv.Field(i), name); ok {
+				return field, true
+			}
+			continue
+		}
+		if !sf.IsExported() || tagName == "-" {
+			continue
+		}
+		if tagName == "" {
+			tagName = sf.Name
+		}
+		if tagName == name {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// resultMap est un objet de réponse dont les clés gardent l'ordre de la requête
+type resultMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newResultMap() *resultMap {
+	return &resultMap{values: make(map[string]interface{})}
+}
+
+func (m *resultMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON encode l'objet en respectant l'ordre des clés
+func (m *resultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// SDL retourne le schéma au format SDL (langage de définition de schéma GraphQL)
+func (s *Schema) SDL() string {
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		if name != s.Query.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{s.Query.Name}, names...)
+
+	var sb strings.Builder
+	for i, name := range names {
+		t := s.Types[name]
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if t.Description != "" {
+			sb.WriteString(strconv.Quote(t.Description) + "\n")
+		}
+		sb.WriteString("type " + t.Name + " {\n")
+
+		fields := make([]string, 0, len(t.Fields))
+		for fieldName := range t.Fields {
+			fields = append(fields, fieldName)
+		}
+		sort.Strings(fields)
+		for _, fieldName := range fields {
+			f := t.Fields[fieldName]
+			if f.Description != "" {
+				sb.WriteString("  " + strconv.Quote(f.Description) + "\n")
+			}
+			sb.WriteString("  " + fieldName)
+			if len(f.Args) > 0 {
+				args := make([]string, len(f.Args))
+				for j, arg := range f.Args {
+					args[j] = arg.Name + ": " + arg.Type
+				}
+				sb.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sb.WriteString(": " + f.Type + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
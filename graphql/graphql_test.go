@@ -0,0 +1,258 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testArtist struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Genres []string `json:"genres"`
+}
+
+type testAlbum struct {
+	Title string `json:"title"`
+}
+
+var testArtists = []testArtist{
+	{ID: 1, Name: "Queen", Genres: []string{"rock"}},
+	{ID: 2, Name: "Daft Punk"},
+}
+
+// testSchema construit un petit schéma ; albumCalls compte les appels du résolveur d'albums
+func testSchema(albumCalls *int) *Schema {
+	return NewSchema(
+		&Object{
+			Name: "Query",
+			Fields: map[string]*Field{
+				"artists": {
+					Type: "[Artist]",
+					Resolve: func(_ context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+						return []interface{}{testArtists}, nil
+					},
+				},
+				"artist": {
+					Type: "Artist",
+					Args: []Arg{{Name: "id", Type: "Int!"}},
+					Resolve: func(_ context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+						for _, a := range testArtists {
+							if a.ID == args["id"] {
+								return []interface{}{a}, nil
+							}
+						}
+						return []interface{}{nil}, nil
+					},
+				},
+				"failing": {
+					Type: "String",
+					Resolve: func(context.Context, []interface{}, map[string]interface{}) ([]interface{}, error) {
+						return nil, errors.New("source indisponible")
+					},
+				},
+				"panicking": {
+					Type: "String",
+					Resolve: func(context.Context, []interface{}, map[string]interface{}) ([]interface{}, error) {
+						panic("boum")
+					},
+				},
+			},
+		},
+		&Object{
+			Name: "Artist",
+			Fields: map[string]*Field{
+				"id":     {Type: "Int"},
+				"name":   {Type: "String"},
+				"genres": {Type: "[String]"},
+				"albums": {
+					Type: "[Album]",
+					Resolve: func(_ context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+						*albumCalls++
+						values := make([]interface{}, len(parents))
+						for i, parent := range parents {
+							values[i] = []testAlbum{{Title: parent.(testArtist).Name + " I"}}
+						}
+						return values, nil
+					},
+				},
+				"similar": {
+					Type: "[Artist]",
+					Resolve: func(_ context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+						values := make([]interface{}, len(parents))
+						for i := range parents {
+							values[i] = testArtists
+						}
+						return values, errors.New("liste incomplète") // Erreur partielle : valeurs conservées
+					},
+				},
+			},
+		},
+		&Object{
+			Name:   "Album",
+			Fields: map[string]*Field{"title": {Type: "String"}},
+		},
+	)
+}
+
+// execute exécute une requête et retourne la réponse encodée en JSON
+func execute(t *testing.T, s *Schema, req Request) string {
+	t.Helper()
+	data, err := json.Marshal(s.Execute(context.Background(), req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExecute(t *testing.T) {
+	var albumCalls int
+	s := testSchema(&albumCalls)
+
+	got := execute(t, s, Request{
+		Query: `query Q($withGenres: Boolean!, $id: Int = 2) {
+			artists { __typename name ...Albums genres @include(if: $withGenres) id @skip(if: true) }
+			one: artist(id: $id) { name }
+			missing: artist(id: 99) { name }
+		}
+		fragment Albums on Artist { albums { title } }`,
+		Variables: map[string]interface{}{"withGenres": true},
+	})
+	want := `{"data":{` +
+		`"artists":[` +
+		`{"__typename":"Artist","name":"Queen","albums":[{"title":"Queen I"}],"genres":["rock"]},` +
+		`{"__typename":"Artist","name":"Daft Punk","albums":[{"title":"Daft Punk I"}],"genres":null}],` +
+		`"one":{"name":"Daft Punk"},` +
+		`"missing":null}}`
+	if got != want {
+		t.Errorf("réponse :\n%s\nattendu :\n%s", got, want)
+	}
+	if albumCalls != 1 {
+		t.Errorf("résolveur d'albums appelé %d fois, attendu 1 (une fois pour tous les artistes)", albumCalls)
+	}
+}
+
+func TestExecuteVariables(t *testing.T) {
+	var albumCalls int
+	s := testSchema(&albumCalls)
+
+	tests := []struct {
+		vars map[string]interface{}
+		want string
+	}{
+		// Les nombres des variables JSON sont des float64
+		{map[string]interface{}{"id": float64(1)}, `{"data":{"artist":{"name":"Queen"}}}`},
+		{nil, `{"errors":[{"message":"variable $id requise"}]}`},
+		{map[string]interface{}{"id": 1.5}, `{"errors":[{"message":"variable $id : valeur 1.5 invalide pour le type Int!"}]}`},
+		{map[string]interface{}{"id": nil}, `{"errors":[{"message":"variable $id : valeur null interdite pour le type Int!"}]}`},
+	}
+
+	for _, tt := range tests {
+		got := execute(t, s, Request{Query: `query ($id: Int!) { artist(id: $id) { name } }`, Variables: tt.vars})
+		if got != tt.want {
+			t.Errorf("variables %v = %s, attendu %s", tt.vars, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteResolverErrors(t *testing.T) {
+	var albumCalls int
+	s := testSchema(&albumCalls)
+
+	got := execute(t, s, Request{Query: "{ failing panicking artist(id: 1) { similar { id } } }"})
+	want := `{"data":{"failing":null,"panicking":null,"artist":{"similar":[{"id":1},{"id":2}]}},"errors":[` +
+		`{"message":"source indisponible","locations":[{"line":1,"column":3}],"path":["failing"]},` +
+		`{"message":"erreur interne lors de la résolution de Query.panicking: boum","locations":[{"line":1,"column":11}],"path":["panicking"]},` +
+		`{"message":"liste incomplète","locations":[{"line":1,"column":37}],"path":["artist","similar"]}]}`
+	if got != want {
+		t.Errorf("réponse :\n%s\nattendu :\n%s", got, want)
+	}
+}
+
+func TestExecuteValidation(t *testing.T) {
+	var albumCalls int
+	s := testSchema(&albumCalls)
+
+	tests := []struct {
+		req  Request
+		want string
+	}{
+		{Request{}, `paramètre "query" manquant`},
+		{Request{Query: "{ nope }"}, `champ "nope" inconnu sur le type "Query"`},
+		{Request{Query: "{ artist { name } }"}, `argument "id" requis pour le champ "artist"`},
+		{Request{Query: "{ artist(id: 1, x: 2) { name } }"}, `argument "x" inconnu pour le champ "artist"`},
+		{Request{Query: "{ artist(id: $id) { name } }"}, "variable $id non déclarée"},
+		{Request{Query: "{ artists }"}, `le champ "artists" de type [Artist] requiert une sous-sélection`},
+		{Request{Query: "{ artists { name { x } } }"}, `le champ "name" de type String n'accepte pas de sous-sélection`},
+		{Request{Query: "{ artists { ...F } } fragment F on Artist { ...F }"}, `le fragment "F" s'inclut lui-même`},
+		{Request{Query: "{ artists { ...G } }"}, `fragment "G" inconnu`},
+		{Request{Query: "{ artists { ... on Album { title } } }"}, `un fragment sur "Album" ne peut pas s'appliquer au type "Artist"`},
+		{Request{Query: "{ artists { name @upper } }"}, "directive @upper inconnue"},
+		{Request{Query: "mutation { artists { name } }"}, `opération "mutation" non prise en charge (lecture seule)`},
+		{Request{Query: "query A { failing } query B { failing }"}, `plusieurs opérations : "operationName" est requis`},
+		{Request{Query: "query A { failing }", OperationName: "B"}, `opération "B" introuvable`},
+	}
+
+	for _, tt := range tests {
+		resp := s.Execute(context.Background(), tt.req)
+		if resp.Data != nil || len(resp.Errors) == 0 {
+			t.Errorf("%q : data = %v, erreurs = %v ; attendu un refus", tt.req.Query, resp.Data, resp.Errors)
+			continue
+		}
+		if resp.Errors[0].Message != tt.want {
+			t.Errorf("%q = %q, attendu %q", tt.req.Query, resp.Errors[0].Message, tt.want)
+		}
+	}
+	if albumCalls != 0 {
+		t.Errorf("résolveur appelé malgré une requête refusée")
+	}
+}
+
+func TestExecuteLimits(t *testing.T) {
+	var albumCalls int
+	s := testSchema(&albumCalls).WithLimits(Limits{MaxDepth: 3, MaxFields: 10})
+
+	// Fragments imbriqués : chaque niveau double le nombre de champs
+	bomb := "{ artists { ...F3 } } fragment F0 on Artist { id name } " +
+		"fragment F1 on Artist { ...F0 ...F0 } fragment F2 on Artist { ...F1 ...F1 } fragment F3 on Artist { ...F2 ...F2 }"
+
+	tests := []struct {
+		query string
+		want  string // Vide : requête acceptée (les erreurs de résolution restent possibles)
+	}{
+		{"{ artists { similar { albums { title } } } }", "requête trop profonde : 3 niveaux de champs au plus"},
+		{"{ artists { similar { name } } }", ""},
+		{"{ artists { ... on Artist { similar { ... on Artist { albums { title } } } } } }", "requête trop profonde : 3 niveaux de champs au plus"},
+		{"{ artists { id name genres } artist(id: 1) { id name genres } one: artist(id: 2) { id } }", ""},
+		{"{ artists { id name genres } artist(id: 1) { id name genres } one: artist(id: 2) { id name } }", "requête trop complexe : 10 champs au plus"},
+		{bomb, "requête trop complexe : 10 champs au plus"},
+	}
+
+	for _, tt := range tests {
+		resp := s.Execute(context.Background(), Request{Query: tt.query})
+		switch {
+		case tt.want == "" && resp.Data == nil:
+			t.Errorf("%q refusée : %v", tt.query, resp.Errors)
+		case tt.want != "" && (len(resp.Errors) != 1 || resp.Errors[0].Message != tt.want || resp.Data != nil):
+			t.Errorf("%q : data = %v, erreurs = %v ; attendu le refus %q", tt.query, resp.Data, resp.Errors, tt.want)
+		}
+	}
+}
+
+func TestSDL(t *testing.T) {
+	var albumCalls int
+	sdl := testSchema(&albumCalls).SDL()
+	for _, want := range []string{
+		"type Query {\n  artist(id: Int!): Artist\n  artists: [Artist]\n",
+		"type Album {\n  title: String\n}\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL sans %q :\n%s", want, sdl)
+		}
+	}
+	if !strings.HasPrefix(sdl, "type Query {") {
+		t.Errorf("Query doit venir en premier :\n%s", sdl)
+	}
+}
@@ -0,0 +1,642 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// ANALYSE DES REQUÊTES
+// ============================================
+
+// Sous-ensemble de GraphQL pris en charge : opérations query (nommées ou non), variables avec
+// valeurs par défaut, alias, arguments, fragments nommés et en ligne, directives @skip/@include.
+// L'introspection (__schema, __type) n'est pas prise en charge ; seul __typename l'est.
+
+// Document est une requête GraphQL analysée
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation est une opération (query, mutation ou subscription)
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []Variable
+	Selections []Selection
+	Location   Location
+}
+
+// Variable est une variable déclarée par une opération ($id: Int! = 1)
+type Variable struct {
+	Name       string
+	Type       string
+	Default    interface{}
+	HasDefault bool
+}
+
+// Fragment est un fragment nommé (fragment Nom on Type { ... })
+type Fragment struct {
+	Name       string
+	On         string
+	Selections []Selection
+	Location   Location
+}
+
+// Selection est un champ, un fragment nommé (Spread) ou un fragment en ligne (Inline)
+type Selection struct {
+	Alias      string
+	Name       string
+	Args       map[string]interface{}
+	Selections []Selection
+	Spread     string
+	Inline     bool
+	On         string // Condition de type d'un fragment en ligne (vide = tous types)
+	Directives []Directive
+	Location   Location
+}
+
+// ResponseKey retourne la clé du champ dans la réponse (alias ou nom)
+func (s Selection) ResponseKey() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+// Directive est une directive (@skip(if: $x))
+type Directive struct {
+	Name string
+	Args map[string]interface{}
+}
+
+// VarRef est une référence à une variable ($id) dans une valeur
+type VarRef string
+
+// Location est une position (1-based) dans le texte de la requête
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error est une erreur GraphQL telle que renvoyée au client
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Locations) > 0 {
+		return fmt.Sprintf("%s (ligne %d, colonne %d)", e.Message, e.Locations[0].Line, e.Locations[0].Column)
+	}
+	return e.Message
+}
+
+// errorAt crée une erreur positionnée
+func errorAt(loc Location, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}}
+}
+
+// Types de jetons
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+	loc   Location
+}
+
+// lex découpe la requête en jetons (virgules, espaces et commentaires ignorés)
+func lex(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	line, lineStart := 1, 0
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		loc := Location{Line: line, Column: i - lineStart + 1}
+
+		switch {
+		case c == '\n':
+			i++
+			line, lineStart = line+1, i
+		case c == ' ' || c == '\t' || c == '\r' || c == ',' || c == '\uFEFF':
+			i++
+		case c == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case strings.ContainsRune("!$&()[]{}:=@|", c):
+			tokens = append(tokens, token{tokenPunct, string(c), loc})
+			i++
+		case c == '.':
+			if i+2 >= len(runes) || runes[i+1] != '.' || runes[i+2] != '.' {
+				return nil, errorAt(loc, "caractère inattendu %q", c)
+			}
+			tokens = append(tokens, token{tokenPunct, "...", loc})
+			i += 3
+		case c == '_' || isASCIILetter(c):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || isASCIILetter(runes[i]) || isASCIIDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenName, string(runes[start:i]), loc})
+		case c == '-' || isASCIIDigit(c):
+			start := i
+			kind := tokenInt
+			i++
+			for i < len(runes) && (isASCIIDigit(runes[i]) || strings.ContainsRune(".eE+-", runes[i])) {
+				if !isASCIIDigit(runes[i]) {
+					kind = tokenFloat
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind, string(runes[start:i]), loc})
+		case c == '"':
+			value, next, err := lexString(runes, i)
+			if err != nil {
+				return nil, errorAt(loc, "%s", err.Error())
+			}
+			tokens = append(tokens, token{tokenString, value, loc})
+			for ; i < next; i++ {
+				if runes[i] == '\n' { // Chaînes """ sur plusieurs lignes
+					line, lineStart = line+1, i+1
+				}
+			}
+		default:
+			return nil, errorAt(loc, "caractère inattendu %q", c)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, loc: Location{Line: line, Column: len(runes) - lineStart + 1}})
+	return tokens, nil
+}
+
+// lexString lit une chaîne "..." (avec échappements) ou """...""" à partir de runes[i]
+func lexString(runes []rune, i int) (string, int, error) {
+	if i+2 < len(runes) && runes[i+1] == '"' && runes[i+2] == '"' {
+		end := strings.Index(string(runes[i+3:]), `"""`)
+		if end < 0 {
+			return "", 0, fmt.Errorf("chaîne \"\"\" non terminée")
+		}
+		body := []rune(string(runes[i+3:])[:end])
+		return strings.TrimSpace(string(body)), i + 3 + len(body) + 3, nil
+	}
+
+	var sb strings.Builder
+	for j := i + 1; j < len(runes); j++ {
+		switch c := runes[j]; c {
+		case '"':
+			return sb.String(), j + 1, nil
+		case '\n':
+			return "", 0, fmt.Errorf("chaîne non terminée")
+		case '\\':
+			j++
+			if j >= len(runes) {
+				return "", 0, fmt.Errorf("chaîne non terminée")
+			}
+			switch e := runes[j]; e {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case 'b':
+				sb.WriteRune('\b')
+			case 'f':
+				sb.WriteRune('\f')
+			case 'u':
+				if j+4 >= len(runes) {
+					return "", 0, fmt.Errorf("échappement \\u invalide")
+				}
+				code, err := strconv.ParseUint(string(runes[j+1:j+5]), 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("échappement \\u invalide")
+				}
+				sb.WriteRune(rune(code))
+				j += 4
+			default:
+				sb.WriteRune(e)
+			}
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return "", 0, fmt.Errorf("chaîne non terminée")
+}
+
+func isASCIILetter(c rune) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isASCIIDigit(c rune) bool  { return c >= '0' && c <= '9' }
+
+// maxNesting borne l'imbrication des sélections et des valeurs dès l'analyse, pour protéger
+// la pile de la descente récursive ; les limites du schéma (Limits) s'appliquent ensuite
+const maxNesting = 100
+
+// parser analyse les jetons par descente récursive
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int // Sélections et valeurs ouvertes
+}
+
+// Parse analyse une requête GraphQL
+func Parse(src string) (*Document, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+
+	for p.peek().kind != tokenEOF {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokenPunct && tok.value == "{":
+			op := &Operation{Type: "query", Location: tok.loc}
+			if op.Selections, err = p.selectionSet(); err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case tok.kind == tokenName && (tok.value == "query" || tok.value == "mutation" || tok.value == "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case tok.kind == tokenName && tok.value == "fragment":
+			frag, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := doc.Fragments[frag.Name]; dup {
+				return nil, errorAt(frag.Location, "fragment %q défini plusieurs fois", frag.Name)
+			}
+			doc.Fragments[frag.Name] = frag
+		default:
+			return nil, p.unexpected(tok)
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, &Error{Message: "la requête ne contient aucune opération"}
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+// enter ouvre un niveau d'imbrication (à refermer par leave)
+func (p *parser) enter(loc Location) error {
+	if p.depth++; p.depth > maxNesting {
+		return errorAt(loc, "imbrication trop profonde (%d niveaux au plus)", maxNesting)
+	}
+	return nil
+}
+
+func (p *parser) leave() { p.depth-- }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// is indique si le jeton courant est la ponctuation donnée
+func (p *parser) is(punct string) bool {
+	tok := p.peek()
+	return tok.kind == tokenPunct && tok.value == punct
+}
+
+// expect consomme la ponctuation attendue
+func (p *parser) expect(punct string) error {
+	if !p.is(punct) {
+		return p.unexpected(p.peek())
+	}
+	p.next()
+	return nil
+}
+
+// name consomme un nom
+func (p *parser) name() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokenName {
+		return "", p.unexpected(tok)
+	}
+	p.next()
+	return tok.value, nil
+}
+
+func (p *parser) unexpected(tok token) *Error {
+	if tok.kind == tokenEOF {
+		return errorAt(tok.loc, "fin de requête inattendue")
+	}
+	return errorAt(tok.loc, "jeton inattendu %q", tok.value)
+}
+
+// operation : query Nom($a: Int = 1) @dir { ... }
+func (p *parser) operation() (*Operation, error) {
+	tok := p.next()
+	op := &Operation{Type: tok.value, Location: tok.loc}
+	if p.peek().kind == tokenName {
+		op.Name = p.next().value
+	}
+
+	if p.is("(") {
+		p.next()
+		for !p.is(")") {
+			if err := p.expect("$"); err != nil {
+				return nil, err
+			}
+			var v Variable
+			var err error
+			if v.Name, err = p.name(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if v.Type, err = p.typeRef(); err != nil {
+				return nil, err
+			}
+			if p.is("=") {
+				p.next()
+				if v.Default, err = p.value(true); err != nil {
+					return nil, err
+				}
+				v.HasDefault = true
+			}
+			op.Variables = append(op.Variables, v)
+		}
+		p.next()
+	}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	var err error
+	op.Selections, err = p.selectionSet()
+	return op, err
+}
+
+// fragment : fragment Nom on Type { ... }
+func (p *parser) fragment() (*Fragment, error) {
+	tok := p.next()
+	frag := &Fragment{Location: tok.loc}
+	var err error
+	if frag.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if on, err := p.name(); err != nil || on != "on" {
+		return nil, errorAt(p.peek().loc, "\"on\" attendu après le nom du fragment")
+	}
+	if frag.On, err = p.name(); err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	frag.Selections, err = p.selectionSet()
+	return frag, err
+}
+
+// typeRef : Int, Int!, [String!]!
+func (p *parser) typeRef() (string, error) {
+	var t string
+	if p.is("[") {
+		if err := p.enter(p.peek().loc); err != nil {
+			return "", err
+		}
+		defer p.leave()
+		p.next()
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		t = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		t = name
+	}
+	if p.is("!") {
+		p.next()
+		t += "!"
+	}
+	return t, nil
+}
+
+// selectionSet : { champ alias: champ(arg: 1) { ... } ...Fragment ... on Type { ... } }
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.enter(p.peek().loc); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.is("}") {
+		if p.peek().kind == tokenEOF {
+			return nil, p.unexpected(p.peek())
+		}
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	p.next()
+	if len(selections) == 0 {
+		return nil, errorAt(p.tokens[p.pos-1].loc, "sélection vide")
+	}
+	return selections, nil
+}
+
+func (p *parser) selection() (Selection, error) {
+	sel := Selection{Location: p.peek().loc}
+	var err error
+
+	if p.is("...") {
+		p.next()
+		if p.peek().kind == tokenName && p.peek().value != "on" {
+			sel.Spread = p.next().value
+			sel.Directives, err = p.directives()
+			return sel, err
+		}
+		sel.Inline = true
+		if p.peek().kind == tokenName {
+			p.next() // "on"
+			if sel.On, err = p.name(); err != nil {
+				return sel, err
+			}
+		}
+		if sel.Directives, err = p.directives(); err != nil {
+			return sel, err
+		}
+		sel.Selections, err = p.selectionSet()
+		return sel, err
+	}
+
+	if sel.Name, err = p.name(); err != nil {
+		return sel, err
+	}
+	if p.is(":") {
+		p.next()
+		sel.Alias = sel.Name
+		if sel.Name, err = p.name(); err != nil {
+			return sel, err
+		}
+	}
+	if sel.Args, err = p.arguments(); err != nil {
+		return sel, err
+	}
+	if sel.Directives, err = p.directives(); err != nil {
+		return sel, err
+	}
+	if p.is("{") {
+		sel.Selections, err = p.selectionSet()
+	}
+	return sel, err
+}
+
+// arguments : (nom: valeur, ...)
+func (p *parser) arguments() (map[string]interface{}, error) {
+	if !p.is("(") {
+		return nil, nil
+	}
+	p.next()
+	args := make(map[string]interface{})
+	for !p.is(")") {
+		tok := p.peek()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := args[name]; dup {
+			return nil, errorAt(tok.loc, "argument %q répété", name)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if args[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+	}
+	p.next()
+	return args, nil
+}
+
+// directives : @nom(arg: valeur) ...
+func (p *parser) directives() ([]Directive, error) {
+	var directives []Directive
+	for p.is("@") {
+		p.next()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, Directive{Name: name, Args: args})
+	}
+	return directives, nil
+}
+
+// value lit une valeur littérale ; les variables sont interdites dans les valeurs par défaut (constant)
+func (p *parser) value(constant bool) (interface{}, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenInt:
+		n, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, errorAt(tok.loc, "entier invalide %q", tok.value)
+		}
+		return n, nil
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, errorAt(tok.loc, "nombre invalide %q", tok.value)
+		}
+		return f, nil
+	case tokenString:
+		return tok.value, nil
+	case tokenName:
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return tok.value, nil // Valeur d'énumération, traitée comme une chaîne
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, errorAt(tok.loc, "variable interdite dans une valeur constante")
+			}
+			name, err := p.name()
+			return VarRef(name), err
+		case "[":
+			if err := p.enter(tok.loc); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			list := []interface{}{}
+			for !p.is("]") {
+				if p.peek().kind == tokenEOF {
+					return nil, p.unexpected(p.peek())
+				}
+				item, err := p.value(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			p.next()
+			return list, nil
+		case "{":
+			if err := p.enter(tok.loc); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			obj := map[string]interface{}{}
+			for !p.is("}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if obj[name], err = p.value(constant); err != nil {
+					return nil, err
+				}
+			}
+			p.next()
+			return obj, nil
+		}
+	}
+	return nil, p.unexpected(tok)
+}
@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# Commentaire ignoré
+		query Artists($q: String = "rock", $ids: [Int!]!) @cached {
+			top: artists(q: $q, perPage: 5, ratio: 1.5, exact: true, sort: NAME, genre: ["pop", null], range: {from: 1, to: -2}) {
+				name
+				...Details @include(if: true)
+				... on Artist { id }
+				... @skip(if: false) { genres }
+			}
+		}
+		fragment Details on Artist { albums { name } }
+		{ genres { name } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Operations) != 2 {
+		t.Fatalf("%d opérations, attendu 2", len(doc.Operations))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Artists" || op.Location != (Location{Line: 3, Column: 3}) {
+		t.Errorf("opération = %s %s %+v", op.Type, op.Name, op.Location)
+	}
+	wantVars := []Variable{
+		{Name: "q", Type: "String", Default: "rock", HasDefault: true},
+		{Name: "ids", Type: "[Int!]!"},
+	}
+	if !reflect.DeepEqual(op.Variables, wantVars) {
+		t.Errorf("variables = %+v, attendu %+v", op.Variables, wantVars)
+	}
+
+	top := op.Selections[0]
+	if top.Alias != "top" || top.Name != "artists" || top.ResponseKey() != "top" {
+		t.Errorf("alias = %q, nom = %q", top.Alias, top.Name)
+	}
+	wantArgs := map[string]interface{}{
+		"q":       VarRef("q"),
+		"perPage": 5,
+		"ratio":   1.5,
+		"exact":   true,
+		"sort":    "NAME",
+		"genre":   []interface{}{"pop", nil},
+		"range":   map[string]interface{}{"from": 1, "to": -2},
+	}
+	if !reflect.DeepEqual(top.Args, wantArgs) {
+		t.Errorf("arguments = %#v, attendu %#v", top.Args, wantArgs)
+	}
+
+	sels := top.Selections
+	if len(sels) != 4 {
+		t.Fatalf("%d sélections, attendu 4", len(sels))
+	}
+	if sels[1].Spread != "Details" || !reflect.DeepEqual(sels[1].Directives, []Directive{{Name: "include", Args: map[string]interface{}{"if": true}}}) {
+		t.Errorf("fragment nommé = %+v", sels[1])
+	}
+	if !sels[2].Inline || sels[2].On != "Artist" || sels[2].Selections[0].Name != "id" {
+		t.Errorf("fragment en ligne = %+v", sels[2])
+	}
+	if !sels[3].Inline || sels[3].On != "" || sels[3].Directives[0].Name != "skip" {
+		t.Errorf("fragment en ligne sans type = %+v", sels[3])
+	}
+
+	frag := doc.Fragments["Details"]
+	if frag == nil || frag.On != "Artist" || frag.Selections[0].Selections[0].Name != "name" {
+		t.Errorf("fragment = %+v", frag)
+	}
+	if anon := doc.Operations[1]; anon.Type != "query" || anon.Name != "" || anon.Selections[0].Name != "genres" {
+		t.Errorf("opération anonyme = %+v", anon)
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	tests := []struct {
+		literal, want string
+	}{
+		{`"simple"`, "simple"},
+		{`"a\"b\\c\nd"`, "a\"b\\c\nd"},
+		{`"été"`, "été"},
+		{`"""  bloc "entre" guillemets
+  """`, `bloc "entre" guillemets`},
+	}
+
+	for _, tt := range tests {
+		doc, err := Parse(`{ a(s: ` + tt.literal + `) }`)
+		if err != nil {
+			t.Errorf("%s : %v", tt.literal, err)
+			continue
+		}
+		if got := doc.Operations[0].Selections[0].Args["s"]; got != tt.want {
+			t.Errorf("%s = %q, attendu %q", tt.literal, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		message string
+		loc     Location
+	}{
+		{"", "la requête ne contient aucune opération", Location{}},
+		{"{ artists", "fin de requête inattendue", Location{1, 10}},
+		{"{ }", "sélection vide", Location{1, 3}},
+		{"{ a }\n}", `jeton inattendu "}"`, Location{2, 1}},
+		{"{ a.b }", `caractère inattendu '.'`, Location{1, 4}},
+		{`{ a(s: "abc) }`, "chaîne non terminée", Location{1, 8}},
+		{`{ a(s: "\u00zz") }`, `échappement \u invalide`, Location{1, 8}},
+		{"{ a(x: 1, x: 2) }", `argument "x" répété`, Location{1, 11}},
+		{"query ($a: Int = $b) { a }", "variable interdite dans une valeur constante", Location{1, 18}},
+		{"fragment F { a } { a }", `"on" attendu après le nom du fragment`, Location{1, 12}},
+		{"fragment F on T { a }\nfragment F on T { b }\n{ a }", `fragment "F" défini plusieurs fois`, Location{2, 1}},
+		{strings.Repeat("{ a ", 101) + strings.Repeat("}", 101), "imbrication trop profonde (100 niveaux au plus)", Location{1, 401}},
+		{"{ a(x: " + strings.Repeat("[", 101) + ") }", "imbrication trop profonde (100 niveaux au plus)", Location{1, 107}},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		gqlErr, ok := err.(*Error)
+		if !ok {
+			t.Errorf("Parse(%q) : erreur %v, attendu *Error", tt.src, err)
+			continue
+		}
+		if gqlErr.Message != tt.message {
+			t.Errorf("Parse(%q) = %q, attendu %q", tt.src, gqlErr.Message, tt.message)
+		}
+		var loc Location
+		if len(gqlErr.Locations) > 0 {
+			loc = gqlErr.Locations[0]
+		}
+		if loc != tt.loc {
+			t.Errorf("Parse(%q) : position %+v, attendu %+v", tt.src, loc, tt.loc)
+		}
+	}
+}
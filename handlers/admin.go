@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"groupie-tracker-ng/config"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// ADMINISTRATION (/admin)
+// ============================================
+
+// admin regroupe l'état de l'administration : jeton (fixé par Configure, remplacé au
+// rechargement de la configuration), fonction de rechargement et dernier échec de rechargement
+var admin struct {
+	mu        sync.Mutex
+	token     string
+	reload    func() (config.Config, error)
+	reloadErr string
+}
+
+// adminNotices sont les résultats d'action affichés sur /admin (?done=...)
+var adminNotices = map[string]bool{
+	"refresh": true, "refresh_running": true, "evicted": true,
+	"not_cached": true, "invalid_id": true, "reloaded": true,
+}
+
+// SetConfigReloader fixe la fonction qui relit la configuration (fichier, environnement, options),
+// appelée par l'action de rechargement de /admin
+func SetConfigReloader(reload func() (config.Config, error)) {
+	admin.mu.Lock()
+	admin.reload = reload
+	admin.mu.Unlock()
+}
+
+// setAdminToken remplace le jeton d'administration (vide : administration désactivée)
+func setAdminToken(token string) {
+	admin.mu.Lock()
+	admin.token = token
+	admin.mu.Unlock()
+}
+
+// adminToken retourne le jeton d'administration
+func adminToken() string {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+	return admin.token
+}
+
+// requireAdmin n'accepte que les requêtes portant le jeton d'administration : en-tête
+// "Authorization: Bearer <jeton>", ou authentification Basic dont le mot de passe est le jeton
+// (utilisateur libre). Sans jeton configuré, l'administration n'existe pas (404).
+// Le navigateur renvoyant seul l'authentification Basic, les actions (POST) faites ainsi
+// doivent aussi porter le jeton anti-CSRF des formulaires de la page.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	token := adminToken()
+	if token == "" {
+		utils.RenderError(w, r, http.StatusNotFound, "error.page_not_found")
+		return false
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && sameToken(bearer, token) {
+		return true
+	}
+	if _, password, ok := r.BasicAuth(); ok && sameToken(password, token) {
+		if r.Method == http.MethodPost && !sameToken(r.PostFormValue("csrf"), csrfToken(token)) {
+			utils.RenderError(w, r, http.StatusForbidden, "error.csrf")
+			return false
+		}
+		return true
+	}
+
+	w.Header().Set("WWW-Authenticate", `Basic realm="Groupie Tracker admin", charset="UTF-8"`)
+	utils.RenderError(w, r, http.StatusUnauthorized, "error.unauthorized")
+	return false
+}
+
+// sameToken compare deux jetons en temps constant
+func sameToken(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// csrfToken dérive du jeton d'administration le jeton anti-CSRF des formulaires ;
+// il change avec le jeton d'administration
+func csrfToken(token string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte("admin-csrf"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AdminHandler affiche l'état des caches, du token Spotify et des rafraîchissements (/admin)
+func AdminHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+
+	now := time.Now()
+	st := apiClient.Status()
+	notice := ""
+	if done := r.URL.Query().Get("done"); adminNotices[done] {
+		notice = utils.T(r, "admin.done."+done)
+	}
+	admin.mu.Lock()
+	reloadErr := admin.reloadErr
+	admin.mu.Unlock()
+
+	renderTemplate(w, r, "admin.html", map[string]interface{}{
+		"Title":       utils.T(r, "admin.title"),
+		"Status":      st,
+		"Fresh":       st.CatalogueFresh(now),
+		"TokenValid":  st.TokenValid(now),
+		"Index":       searchIndex.Load(),
+		"Refreshing":  refreshing.Load(),
+		"Notice":      notice,
+		"ReloadError": reloadErr,
+		"CSRF":        csrfToken(adminToken()),
+	})
+}
+
+// AdminRefreshHandler expire le catalogue en cache et lance son rafraîchissement (POST /admin/refresh)
+func AdminRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminAction(w, r) {
+		return
+	}
+	apiClient.ExpireArtists()
+	done := "refresh_running"
+	if refreshInBackground(r.Context()) {
+		done = "refresh"
+		utils.Logger(r.Context()).Info("Rafraîchissement du catalogue demandé depuis l'administration")
+	}
+	redirectAdmin(w, r, done)
+}
+
+// AdminEvictHandler retire du cache le détail d'un artiste (POST /admin/evict, champ artist)
+func AdminEvictHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminAction(w, r) {
+		return
+	}
+	artistID, err := strconv.Atoi(r.PostFormValue("artist"))
+	done := "invalid_id"
+	if err == nil && artistID > 0 {
+		done = "not_cached"
+		if apiClient.EvictArtistDetail(artistID) {
+			done = "evicted"
+			utils.Logger(r.Context()).Info("Détail d'artiste retiré du cache", "artist_id", artistID)
+		}
+	}
+	redirectAdmin(w, r, done)
+}
+
+// AdminReloadHandler relit la configuration et applique sans redémarrage les réglages Spotify,
+// le fichier des concerts, le classement des suggestions et le jeton d'administration
+// (POST /admin/reload) ; les autres réglages demandent un redémarrage
+func AdminReloadHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminAction(w, r) {
+		return
+	}
+
+	admin.mu.Lock()
+	reload := admin.reload
+	admin.mu.Unlock()
+	var cfg config.Config
+	err := errors.New("rechargement non disponible")
+	if reload != nil {
+		cfg, err = reload()
+	}
+
+	if err == nil {
+		err = loadConcerts(cfg.ConcertsFile)
+	}
+
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+	if err != nil {
+		admin.reloadErr = err.Error()
+		utils.Logger(r.Context()).Error("Rechargement de la configuration impossible", "error", err)
+		redirectAdmin(w, r, "")
+		return
+	}
+	apiClient.Configure(cfg.SpotifySettings())
+	setRankingWeights(cfg.Ranking.RankingWeights)
+	admin.token = cfg.AdminToken
+	admin.reloadErr = ""
+	utils.Logger(r.Context()).Info("Configuration rechargée depuis l'administration")
+	redirectAdmin(w, r, "reloaded")
+}
+
+// requireAdminAction n'accepte que les POST authentifiés
+func requireAdminAction(w http.ResponseWriter, r *http.Request) bool {
+	if !requireAdmin(w, r) {
+		return false
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return false
+	}
+	return true
+}
+
+// redirectAdmin renvoie vers /admin après une action, avec son résultat (vide : aucun message)
+func redirectAdmin(w http.ResponseWriter, r *http.Request, done string) {
+	target := "/admin"
+	if done != "" {
+		target += "?done=" + done
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
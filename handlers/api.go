@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// API JSON v1 (/api/v1/...)
+// ============================================
+
+// APIResponse est l'enveloppe commune des réponses de l'API JSON
+type APIResponse struct {
+	Data interface{} `json:"data"`
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+// APIErrorResponse est l'enveloppe commune des erreurs de l'API JSON
+type APIErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// APIError décrit une erreur : statut HTTP, code stable pour les clients et message lisible
+type APIError struct {
+	Status  int      `json:"status"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
+
+// Codes d'erreur de l'API JSON
+const (
+	errCodeBadRequest       = "bad_request"
+	errCodeNotFound         = "not_found"
+	errCodeMethodNotAllowed = "method_not_allowed"
+	errCodeUnauthenticated  = "upstream_unauthenticated"
+	errCodeRateLimited      = "rate_limited"
+	errCodeUpstream         = "upstream_error"
+	errCodeTimeout          = "upstream_timeout"
+)
+
+// ArtistListMeta accompagne la liste d'artistes : pagination et requête interprétée
+type ArtistListMeta struct {
+	Pagination  utils.Pagination     `json:"pagination"`
+	Filters     models.FilterOptions `json:"filters"`
+	Query       string               `json:"query,omitempty"`
+	QueryErrors []string             `json:"queryErrors,omitempty"`
+}
+
+// FacetItem est une valeur de filtre (genre, lieu) avec le nombre d'artistes correspondants
+type FacetItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// APIRoute décrit une route de l'API JSON ; la même table sert à l'enregistrement
+// des routes (cmd/main.go) et à la génération du document OpenAPI
+type APIRoute struct {
+	Pattern     string // Motif http.ServeMux, ex. "/api/v1/artists/{id}"
+	Handler     http.HandlerFunc
+	Summary     string
+	PathParams  []utils.FilterParam
+	QueryParams []utils.FilterParam
+	Data        interface{} // Valeur du type renvoyé dans "data" (seul le type compte)
+	Meta        interface{} // Valeur du type renvoyé dans "meta" (nil = absent)
+	Errors      []int       // Statuts d'erreur possibles
+}
+
+// upstreamStatuses sont les statuts d'erreur dus à Spotify (voir classifyUpstream)
+var upstreamStatuses = []int{http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusGatewayTimeout}
+
+// APIRoutes liste les routes de l'API JSON v1
+var APIRoutes = []APIRoute{
+	{
+		Pattern: "/api/v1/artists",
+		Handler: APIArtistsHandler,
+		Summary: "Liste des artistes avec recherche, filtres, tri et pagination",
+		QueryParams: append([]utils.FilterParam{
+			{Name: "q", Type: "string", Description: "Recherche (mots libres, \"expression\", -exclusion, genre:rock year:>=2000 ...)"},
+		}, utils.FilterParams...),
+		Data:   []models.Artist{},
+		Meta:   ArtistListMeta{},
+		Errors: upstreamStatuses,
+	},
+	{
+		Pattern:    "/api/v1/artists/{id}",
+		Handler:    APIArtistDetailHandler,
+		Summary:    "Détail complet d'un artiste",
+		PathParams: []utils.FilterParam{{Name: "id", Type: "integer", Description: "ID de l'artiste"}},
+		Data:       models.ArtistDetail{},
+		Errors:     append([]int{http.StatusBadRequest, http.StatusNotFound}, upstreamStatuses...),
+	},
+	{
+		Pattern: "/api/v1/genres",
+		Handler: APIGenresHandler,
+		Summary: "Genres du catalogue et nombre d'artistes par genre",
+		Data:    []FacetItem{},
+		Errors:  upstreamStatuses,
+	},
+	{
+		Pattern: "/api/v1/locations",
+		Handler: APILocationsHandler,
+		Summary: "Lieux de concerts et nombre d'artistes par lieu",
+		Data:    []FacetItem{},
+		Errors:  upstreamStatuses,
+	},
+}
+
+// writeJSON écrit une réponse JSON avec le statut donné
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Erreur lors de l'encodage JSON: %v", err)
+	}
+}
+
+// writeAPIError écrit une erreur JSON dans l'enveloppe commune ; key est la clé du message, traduit dans la langue de la requête
+func writeAPIError(w http.ResponseWriter, r *http.Request, status int, code, key string, details ...string) {
+	writeJSON(w, status, APIErrorResponse{Error: APIError{
+		Status:  status,
+		Code:    code,
+		Message: utils.T(r, key),
+		Details: details,
+	}})
+}
+
+// requireGET refuse toute méthode autre que GET avec une erreur JSON
+func requireGET(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet {
+		return true
+	}
+	w.Header().Set("Allow", http.MethodGet)
+	writeAPIError(w, r, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "error.method_not_allowed")
+	return false
+}
+
+// APIArtistsHandler liste les artistes (mêmes paramètres de recherche, filtres, tri et pagination que /artists)
+func APIArtistsHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	artists, err := apiClient.FetchArtists(r.Context())
+	if err != nil {
+		writeUpstreamError(w, r, err, "error.artists_unavailable")
+		return
+	}
+
+	listing := listArtists(artists, r.URL.Query())
+	page, pagination := utils.Paginate(listing.Artists, listing.Options, r.URL)
+
+	meta := ArtistListMeta{
+		Pagination: pagination,
+		Filters:    listing.Options,
+		Query:      listing.Query,
+	}
+	for _, qe := range listing.Parsed.Errors {
+		meta.QueryErrors = append(meta.QueryErrors, qe.Localize(utils.Language(r)))
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{Data: page, Meta: meta})
+}
+
+// APIArtistDetailHandler retourne le détail complet d'un artiste (/api/v1/artists/{id})
+func APIArtistDetailHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	artistID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || artistID <= 0 {
+		writeAPIError(w, r, http.StatusBadRequest, errCodeBadRequest, "error.invalid_artist_id")
+		return
+	}
+
+	detail, err := apiClient.FetchArtistDetail(r.Context(), artistID)
+	if err != nil {
+		writeUpstreamError(w, r, err, "error.artist_not_found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{Data: detail})
+}
+
+// APIGenresHandler liste les genres du catalogue avec le nombre d'artistes par genre
+func APIGenresHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	artists, err := apiClient.FetchArtists(r.Context())
+	if err != nil {
+		writeUpstreamError(w, r, err, "error.artists_unavailable")
+		return
+	}
+
+	facets := utils.ComputeFacets(artists, models.FilterOptions{}, loadLocationIndex())
+	writeJSON(w, http.StatusOK, APIResponse{Data: facetItems(facets.Genres)})
+}
+
+// APILocationsHandler liste les lieux de concerts avec le nombre d'artistes par lieu
+func APILocationsHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	artists, err := apiClient.FetchArtists(r.Context())
+	if err != nil {
+		writeUpstreamError(w, r, err, "error.artists_unavailable")
+		return
+	}
+
+	facets := utils.ComputeFacets(artists, models.FilterOptions{}, loadLocationIndex())
+	writeJSON(w, http.StatusOK, APIResponse{Data: facetItems(facets.Locations)})
+}
+
+// APINotFoundHandler répond en JSON pour toute route /api/ inconnue
+func APINotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	writeAPIError(w, r, http.StatusNotFound, errCodeNotFound, "error.resource_not_found")
+}
+
+// facetItems convertit des compteurs de filtre en éléments de l'API
+func facetItems(facets []utils.FacetCount) []FacetItem {
+	items := make([]FacetItem, len(facets))
+	for i, f := range facets {
+		items[i] = FacetItem{Name: f.Label, Count: f.Count}
+	}
+	return items
+}
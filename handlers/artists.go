@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
+	"groupie-tracker-ng/api"
 	"groupie-tracker-ng/models"
 	"groupie-tracker-ng/utils"
 )
@@ -12,89 +15,111 @@ import (
 // ArtistsHandler gère la liste des artistes avec filtres et recherche
 func ArtistsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.RenderError(w, http.StatusMethodNotAllowed, "Méthode non autorisée")
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
 		return
 	}
 
-	// Récupérer les artistes depuis l'API Spotify
-	artists, err := apiClient.FetchArtists()
+	// Ne pas attendre l'API Spotify : servir le catalogue en cache (même expiré) et le rafraîchir
+	// en arrière-plan ; la page suit la progression sur /events et se recharge une fois à jour
+	artists, fresh := apiClient.CachedArtists()
+	api.ObserveCache("artists", fresh)
+	last := refreshEvents.state()
 	apiError := ""
-	if err != nil {
-		// Ne pas faire planter la page : afficher une liste vide et un message
+	if !fresh {
+		refreshInBackground(r.Context())
+	}
+	if artists == nil {
+		// Ne pas faire planter la page : afficher une liste vide et, si besoin, le dernier échec
 		artists = []models.Artist{}
-		apiError = err.Error()
+		if last.Stage == api.RefreshFailed {
+			apiError = last.Error
+		}
 	}
 
-	// Appliquer la recherche si présente
-	query := r.URL.Query().Get("q")
-	if query != "" && len(query) >= 1 {
-		artists = utils.SearchArtists(artists, query)
-	}
+	// Recherche, filtres et tri, puis pagination
+	listing := listArtists(artists, r.URL.Query())
+	page, pagination := utils.Paginate(listing.Artists, listing.Options, r.URL)
 
-	// Appliquer les filtres (adaptés pour Spotify)
-	filterOptions := utils.ParseFilterOptions(r.URL.Query())
-	
-	// Appliquer les filtres (année, membres, premier album)
-	artists = utils.FilterArtists(artists, filterOptions)
-	
-	// Filtrer par lieux si demandé
-	if len(filterOptions.Locations) > 0 {
-		artists = utils.FilterArtists(artists, filterOptions)
+	data := map[string]interface{}{
+		"Title":       utils.T(r, "artists.title"),
+		"Artists":     page,
+		"Query":       listing.Query,
+		"QueryErrors": listing.Parsed.Errors,
+		"APIError":    apiError,
 	}
+	addFilterData(data, listing.Options, listing.facets())
+	data["Pagination"] = pagination
+	data["ExportLinks"] = exportLinks(r.URL.Query())
+	data["Refreshing"] = !fresh
+	data["Loading"] = len(artists) == 0 && !fresh
+	data["RefreshSince"] = last.ID
+
+	renderTemplate(w, r, "artists.html", data)
+}
 
-	// Récupérer la liste des lieux populaires pour le filtre
-	locationsList := utils.GetPopularLocations()
+// artistListing est le résultat de la recherche, des filtres et du tri (avant pagination)
+type artistListing struct {
+	Query     string
+	Parsed    utils.ParsedQuery
+	Options   models.FilterOptions // Filtres du formulaire et de la requête structurée
+	Searched  []models.Artist      // Artistes correspondant à la recherche textuelle
+	Artists   []models.Artist      // Artistes filtrés et triés
+	Locations *utils.LocationIndex
+}
 
-	// Préparer les données pour les filtres
-	minYear := ""
-	maxYear := ""
-	if filterOptions.MinYear > 0 {
-		minYear = strconv.Itoa(filterOptions.MinYear)
-	}
-	if filterOptions.MaxYear > 0 {
-		maxYear = strconv.Itoa(filterOptions.MaxYear)
+// listArtists applique aux artistes la recherche "q" (mots libres, "expressions", -exclusions,
+// clé:valeur), les filtres (année, membres, genres, popularité, premier album, lieux) et le tri
+func listArtists(artists []models.Artist, params url.Values) artistListing {
+	query := params.Get("q")
+	parsed := utils.ParseQuery(query, utils.ParseFilterOptions(params))
+	searched := utils.ApplyQuery(artists, parsed)
+	locationIndex := loadLocationIndex()
+
+	filtered := utils.FilterArtists(searched, parsed.Options, locationIndex)
+
+	return artistListing{
+		Query:     query,
+		Parsed:    parsed,
+		Options:   parsed.Options,
+		Searched:  searched,
+		Artists:   utils.SortArtists(filtered, parsed.Options.Sort),
+		Locations: locationIndex,
 	}
-	
-	firstAlbumMin := filterOptions.FirstAlbumMin
-	firstAlbumMax := filterOptions.FirstAlbumMax
+}
 
-	// Vérifier quels nombres de membres sont sélectionnés
-	memberSelected := make(map[int]bool)
-	for _, mc := range filterOptions.MemberCount {
-		memberSelected[mc] = true
-	}
+// facets calcule les compteurs de chaque option de filtre pour la liste
+func (l artistListing) facets() utils.Facets {
+	return utils.ComputeFacets(l.Searched, l.Options, l.Locations)
+}
 
-	// Vérifier quels lieux sont sélectionnés
-	locationSelected := make(map[string]bool)
-	for _, loc := range filterOptions.Locations {
-		locationSelected[loc] = true
+// addFilterData ajoute au template l'état des filtres et les compteurs de chaque option
+func addFilterData(data map[string]interface{}, options models.FilterOptions, facets utils.Facets) {
+	minYear := ""
+	maxYear := ""
+	if options.MinYear > 0 {
+		minYear = strconv.Itoa(options.MinYear)
 	}
-
-	data := map[string]interface{}{
-		"Title":           "Liste des Artistes",
-		"Artists":         artists,
-		"Query":           query,
-		"Locations":       locationsList,
-		"MinYear":         minYear,
-		"MaxYear":         maxYear,
-		"FirstAlbumMin":   firstAlbumMin,
-		"FirstAlbumMax":   firstAlbumMax,
-		"Member1":         memberSelected[1],
-		"Member2":         memberSelected[2],
-		"Member3":         memberSelected[3],
-		"Member4":         memberSelected[4],
-		"Member5":         memberSelected[5],
-		"LocationSelected": locationSelected,
-		"APIError":        apiError,
+	if options.MaxYear > 0 {
+		maxYear = strconv.Itoa(options.MaxYear)
 	}
 
-	renderTemplate(w, "artists.html", data)
+	data["MinYear"] = minYear
+	data["MaxYear"] = maxYear
+	data["FirstAlbumMin"] = options.FirstAlbumMin
+	data["FirstAlbumMax"] = options.FirstAlbumMax
+	data["GenreModeAll"] = options.GenreMode == models.GenreModeAll
+	data["Genres"] = facets.Genres
+	data["Members"] = facets.Members
+	data["Locations"] = facets.Locations
+	data["Sort"] = options.Sort
+	data["SortOptions"] = utils.SortOptions
+	data["PerPage"] = options.PerPage
 }
 
 // ArtistDetailHandler gère la page de détails d'un artiste
 func ArtistDetailHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.RenderError(w, http.StatusMethodNotAllowed, "Méthode non autorisée")
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
 		return
 	}
 
@@ -104,21 +129,47 @@ func ArtistDetailHandler(w http.ResponseWriter, r *http.Request) {
 	// Valider l'ID
 	artistID, err := strconv.Atoi(artistIDStr)
 	if err != nil || artistID <= 0 {
-		utils.RenderError(w, http.StatusBadRequest, "ID d'artiste invalide")
+		utils.RenderError(w, r, http.StatusBadRequest, "error.invalid_artist_id")
 		return
 	}
 
 	// Récupérer les détails complets de l'artiste
-	detail, err := apiClient.FetchArtistDetail(artistID)
+	detail, err := apiClient.FetchArtistDetail(r.Context(), artistID)
 	if err != nil {
-		utils.RenderError(w, http.StatusNotFound, "Artiste non trouvé")
+		renderUpstreamError(w, r, err, "error.artist_not_found")
 		return
 	}
 
 	data := map[string]interface{}{
-		"Title":  "Détails de " + detail.Name,
-		"Artist": detail,
+		"Title":       utils.T(r, "detail.title", detail.Name),
+		"Artist":      detail,
+		"FeedURL":     artistFeedURL(detail.Artist),
+		"CalendarURL": fmt.Sprintf("/artist/%d/concerts.ics", detail.ID),
+	}
+
+	renderTemplate(w, r, "artists_details.html", data)
+}
+
+// ArtistPermalinkHandler redirige l'adresse stable d'un artiste (/spotify/artist/{spotifyId}),
+// utilisée par les calendriers, vers sa page du catalogue. Redirection temporaire : l'ID du
+// catalogue change à chaque rafraîchissement.
+func ArtistPermalinkHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
 	}
 
-	renderTemplate(w, "artists_details.html", data)
+	artists, err := apiClient.FetchArtists(r.Context())
+	if err != nil {
+		renderUpstreamError(w, r, err, "error.artists_unavailable")
+		return
+	}
+	spotifyID := r.PathValue("spotifyId")
+	for _, artist := range artists {
+		if artist.SpotifyID == spotifyID {
+			http.Redirect(w, r, fmt.Sprintf("/artist/%d", artist.ID), http.StatusFound)
+			return
+		}
+	}
+	utils.RenderError(w, r, http.StatusNotFound, "error.artist_not_found")
 }
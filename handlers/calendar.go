@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// CALENDRIERS DE CONCERTS (.ics)
+// ============================================
+
+// Spotify ne fournit pas de concerts : sans fichier de concerts (concertsFile), les calendriers
+// répondent 404 avec une explication plutôt que des calendriers toujours vides.
+
+// ArtistCalendarHandler sert les concerts d'un artiste au format iCalendar (/artist/{id}/concerts.ics)
+func ArtistCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+	if !apiClient.HasConcerts() {
+		utils.RenderError(w, r, http.StatusNotFound, "error.concerts_unavailable")
+		return
+	}
+
+	artistID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || artistID <= 0 {
+		utils.RenderError(w, r, http.StatusBadRequest, "error.invalid_artist_id")
+		return
+	}
+
+	detail, err := apiClient.FetchArtistDetail(r.Context(), artistID)
+	if err != nil {
+		renderUpstreamError(w, r, err, "error.artist_not_found")
+		return
+	}
+
+	serveCalendar(w, r, utils.NewConcertCalendar(utils.T(r, "calendar.artist", detail.Name),
+		requestBaseURL(r), []models.ArtistDetail{*detail}, nil))
+}
+
+// LocationCalendarHandler sert les concerts de tous les artistes dans un lieu
+// (/location/{slug}/concerts.ics, ex. paris-france ou paris pour toutes les villes de ce nom)
+func LocationCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+	if !apiClient.HasConcerts() {
+		utils.RenderError(w, r, http.StatusNotFound, "error.concerts_unavailable")
+		return
+	}
+
+	slug := r.PathValue("slug")
+	wanted := utils.NormalizeLocation(slug)
+	if wanted == "" {
+		utils.RenderError(w, r, http.StatusNotFound, "error.location_not_found")
+		return
+	}
+
+	artists, err := apiClient.FetchArtists(r.Context())
+	if err != nil {
+		renderUpstreamError(w, r, err, "error.artists_unavailable")
+		return
+	}
+	// Index construit au rafraîchissement du catalogue : seuls les artistes du lieu sont relus
+	ids := loadLocationIndex().ArtistIDs([]string{slug})
+	if len(ids) == 0 {
+		utils.RenderError(w, r, http.StatusNotFound, "error.location_not_found")
+		return
+	}
+
+	var performers []models.Artist
+	for _, artist := range artists {
+		if ids[artist.ID] {
+			performers = append(performers, artist)
+		}
+	}
+	byID := make(map[int]models.Artist, len(performers))
+	for _, artist := range performers {
+		byID[artist.ID] = artist
+	}
+	details := []models.ArtistDetail{}
+	for _, rel := range apiClient.Relations(performers) {
+		details = append(details, models.ArtistDetail{Artist: byID[rel.ID], Relations: rel.DatesLocations})
+	}
+
+	// Même règle que le filtre par lieu : forme complète ou ville seule
+	keep := func(location string) bool {
+		city, _, _ := strings.Cut(location, "-")
+		return utils.NormalizeLocation(location) == wanted || utils.NormalizeLocation(city) == wanted
+	}
+	serveCalendar(w, r, utils.NewConcertCalendar(utils.T(r, "calendar.location", utils.LocationLabel(slug)),
+		requestBaseURL(r), details, keep))
+}
+
+// serveCalendar encode le calendrier avant l'envoi, pour répondre par une erreur propre en cas d'échec
+func serveCalendar(w http.ResponseWriter, r *http.Request, cal utils.ICalendar) {
+	var buf bytes.Buffer
+	if err := utils.WriteICalendar(&buf, cal, time.Now()); err != nil {
+		utils.Logger(r.Context()).Error("Erreur lors de l'encodage du calendrier", "error", err)
+		utils.RenderError(w, r, http.StatusInternalServerError, "error.calendar_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="concerts.ics"`)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(buf.Bytes()))
+}
@@ -1,10 +1,107 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
 	"groupie-tracker-ng/api"
+	"groupie-tracker-ng/config"
+	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
 )
 
 var (
 	// Client API Spotify (remplace Groupie Trackers)
 	apiClient = api.NewClient()
+
+	// Index de recherche des suggestions, remplacé d'un bloc à chaque rafraîchissement du catalogue
+	searchIndex atomic.Pointer[utils.SearchIndex]
+
+	// Index des lieux de concerts, reconstruit avec le catalogue et à chaque chargement des concerts
+	locationIndex atomic.Pointer[utils.LocationIndex]
 )
+
+// Configure applique la configuration du serveur ; à appeler au démarrage, avant de servir.
+// Les catalogues de messages et les templates sont chargés ici : une erreur empêche le démarrage.
+func Configure(cfg config.Config) error {
+	messages, err := loadMessages()
+	if err != nil {
+		return fmt.Errorf("catalogues de messages : %w", err)
+	}
+	utils.Messages = messages
+	set, err := loadTemplates(cfg.TemplatesDir, cfg.Dev)
+	if err != nil {
+		return err
+	}
+	utils.Templates = set
+	if assets, err = loadAssets(cfg.StaticDir, cfg.Dev); err != nil {
+		return fmt.Errorf("fichiers statiques : %w", err)
+	}
+	apiClient.Configure(cfg.SpotifySettings())
+	if err := loadConcerts(cfg.ConcertsFile); err != nil {
+		return err
+	}
+	setRankingWeights(cfg.Ranking.RankingWeights)
+	setAdminToken(cfg.AdminToken)
+	releaseStore = loadReleaseStore(cfg.ReleasesFile)
+	return nil
+}
+
+func init() {
+	apiClient.OnArtistsRefresh(rebuildIndexes)
+}
+
+// rebuildIndexes remplace l'index des lieux de concerts puis l'index de recherche, qui inclut les lieux
+func rebuildIndexes(artists []models.Artist) {
+	locations := utils.BuildLocationIndex(apiClient.Relations(artists))
+	locationIndex.Store(locations)
+	searchIndex.Store(utils.BuildSearchIndex(artists, locations))
+}
+
+// currentSearchIndex retourne l'index de recherche, en rafraîchissant le catalogue s'il est absent ou expiré
+func currentSearchIndex(ctx context.Context) (*utils.SearchIndex, error) {
+	if idx := searchIndex.Load(); idx != nil && time.Since(idx.BuiltAt()) < apiClient.CacheTTL() {
+		api.ObserveCache("search_index", true)
+		return idx, nil
+	}
+	api.ObserveCache("search_index", false)
+
+	artists, err := apiClient.FetchArtists(ctx)
+	if idx := searchIndex.Load(); idx != nil {
+		// Index reconstruit par le rafraîchissement, ou ancien index si l'API est indisponible
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Catalogue servi depuis le cache sans rafraîchissement : construire l'index maintenant
+	idx := utils.BuildSearchIndex(artists, loadLocationIndex())
+	searchIndex.CompareAndSwap(nil, idx)
+	return searchIndex.Load(), nil
+}
+
+// loadConcerts lit le fichier des concerts (vide : aucun concert connu) et reconstruit les index
+func loadConcerts(path string) error {
+	entries, err := api.LoadConcerts(path)
+	if err != nil {
+		return err
+	}
+	apiClient.SetConcerts(entries)
+	if artists, _ := apiClient.CachedArtists(); artists != nil {
+		rebuildIndexes(artists)
+	} else {
+		locationIndex.Store(utils.BuildLocationIndex(nil))
+	}
+	return nil
+}
+
+// loadLocationIndex retourne l'index des lieux de concerts du catalogue courant
+func loadLocationIndex() *utils.LocationIndex {
+	if idx := locationIndex.Load(); idx != nil {
+		return idx
+	}
+	return utils.BuildLocationIndex(nil)
+}
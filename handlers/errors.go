@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"groupie-tracker-ng/api"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// ERREURS DU CLIENT SPOTIFY → STATUTS HTTP
+// ============================================
+
+// defaultRetryAfter est proposé aux clients quand Spotify limite les appels sans préciser de délai
+const defaultRetryAfter = 30 * time.Second
+
+// upstreamFailure est la réponse à une erreur du client Spotify
+type upstreamFailure struct {
+	status     int
+	code       string        // Code de l'API JSON
+	key        string        // Clé du message
+	retryAfter time.Duration // En-tête Retry-After (0 : absent)
+}
+
+// classifyUpstream associe une erreur du client Spotify à sa réponse : 404 (message notFoundKey),
+// 401 identifiants refusés, 429 limite d'appels (avec Retry-After), 504 délai dépassé, 502 sinon
+func classifyUpstream(err error, notFoundKey string) upstreamFailure {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		return upstreamFailure{status: http.StatusNotFound, code: errCodeNotFound, key: notFoundKey}
+	case errors.Is(err, api.ErrUnauthenticated):
+		return upstreamFailure{status: http.StatusUnauthorized, code: errCodeUnauthenticated, key: "error.upstream_unauthenticated"}
+	case errors.Is(err, api.ErrRateLimited):
+		retry := api.RetryAfter(err)
+		if retry <= 0 {
+			retry = defaultRetryAfter
+		}
+		return upstreamFailure{status: http.StatusTooManyRequests, code: errCodeRateLimited, key: "error.upstream_rate_limited", retryAfter: retry}
+	case errors.Is(err, api.ErrTimeout):
+		return upstreamFailure{status: http.StatusGatewayTimeout, code: errCodeTimeout, key: "error.upstream_timeout"}
+	}
+	return upstreamFailure{status: http.StatusBadGateway, code: errCodeUpstream, key: "error.upstream_unavailable"}
+}
+
+// prepare journalise l'erreur (sauf 404) et ajoute l'en-tête Retry-After s'il y a lieu
+func (f upstreamFailure) prepare(w http.ResponseWriter, r *http.Request, err error) {
+	if f.status != http.StatusNotFound {
+		utils.Logger(r.Context()).Warn("Erreur du client Spotify", "status", f.status, "error", err)
+	}
+	if f.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.retryAfter.Seconds()))))
+	}
+}
+
+// renderUpstreamError affiche la page d'erreur correspondant à une erreur du client Spotify
+func renderUpstreamError(w http.ResponseWriter, r *http.Request, err error, notFoundKey string) {
+	f := classifyUpstream(err, notFoundKey)
+	f.prepare(w, r, err)
+	utils.RenderError(w, r, f.status, f.key)
+}
+
+// writeUpstreamError écrit l'erreur JSON (enveloppe de l'API) correspondant à une erreur du client Spotify
+func writeUpstreamError(w http.ResponseWriter, r *http.Request, err error, notFoundKey string) {
+	f := classifyUpstream(err, notFoundKey)
+	f.prepare(w, r, err)
+	writeAPIError(w, r, f.status, f.code, f.key)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"groupie-tracker-ng/api"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// PROGRESSION DU RAFRAÎCHISSEMENT (/events, SSE)
+// ============================================
+
+const (
+	// sseHeartbeat est l'intervalle des commentaires envoyés pour garder la connexion ouverte
+	sseHeartbeat = 15 * time.Second
+	// sseBuffer est le nombre d'événements en attente par client
+	sseBuffer = 32
+)
+
+// refreshEvent est une étape de rafraîchissement numérotée (champ id des événements SSE)
+type refreshEvent struct {
+	ID int
+	api.RefreshEvent
+}
+
+// refreshBroker diffuse les étapes des rafraîchissements du catalogue aux clients de /events
+type refreshBroker struct {
+	mu      sync.Mutex
+	clients map[chan refreshEvent]struct{}
+	last    refreshEvent // Dernière étape, renvoyée aux nouveaux clients
+	closed  bool
+}
+
+var (
+	refreshEvents = &refreshBroker{clients: make(map[chan refreshEvent]struct{})}
+
+	// refreshing est vrai pendant un rafraîchissement lancé par refreshInBackground
+	refreshing atomic.Bool
+	// serverCtx borne les tâches lancées par refreshInBackground (voir SetServerContext)
+	serverCtx = context.Background()
+	// background compte ces tâches, attendues par WaitBackground avant la sauvegarde de l'état
+	background sync.WaitGroup
+)
+
+func init() {
+	apiClient.OnRefreshProgress(refreshEvents.publish)
+}
+
+// publish numérote l'étape et l'envoie à chaque client sans jamais bloquer le rafraîchissement :
+// la file d'un client trop lent perd ses étapes les plus anciennes
+func (b *refreshBroker) publish(event api.RefreshEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.last = refreshEvent{ID: b.last.ID + 1, RefreshEvent: event}
+	for ch := range b.clients {
+		select {
+		case ch <- b.last:
+			continue
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- b.last:
+		default:
+		}
+	}
+}
+
+// subscribe inscrit un client et retourne sa file avec la dernière étape connue.
+// Après close, la file retournée est déjà fermée.
+func (b *refreshBroker) subscribe() (chan refreshEvent, refreshEvent) {
+	ch := make(chan refreshEvent, sseBuffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, b.last
+	}
+	b.clients[ch] = struct{}{}
+	return ch, b.last
+}
+
+// unsubscribe désinscrit un client
+func (b *refreshBroker) unsubscribe(ch chan refreshEvent) {
+	b.mu.Lock()
+	delete(b.clients, ch)
+	b.mu.Unlock()
+}
+
+// close ferme les files de tous les clients : leurs flux se terminent
+func (b *refreshBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.clients {
+		close(ch)
+		delete(b.clients, ch)
+	}
+}
+
+// CloseEventStreams termine les flux /events en cours. Un flux SSE n'est jamais inactif :
+// sans cela, l'arrêt du serveur attendrait ces connexions jusqu'à son délai maximum.
+func CloseEventStreams() {
+	refreshEvents.close()
+}
+
+// state retourne la dernière étape publiée (ID 0 si aucune)
+func (b *refreshBroker) state() refreshEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.last
+}
+
+// SetServerContext fixe la durée de vie du serveur (à appeler avant de servir) : son annulation
+// interrompt les rafraîchissements du catalogue, que WaitBackground attend ensuite
+func SetServerContext(ctx context.Context) {
+	serverCtx = ctx
+	apiClient.SetLifetime(ctx)
+}
+
+// WaitBackground attend la fin des rafraîchissements lancés en arrière-plan, au plus jusqu'à
+// l'annulation de ctx (false : des tâches tournaient encore)
+func WaitBackground(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// refreshInBackground lance un rafraîchissement du catalogue, sauf s'il y en a déjà un en cours (false).
+// Il survit à la requête de ctx, qui l'a provoqué et dont il garde l'identifiant dans les journaux,
+// mais pas au serveur : il s'arrête à l'annulation de serverCtx.
+func refreshInBackground(ctx context.Context) bool {
+	if !refreshing.CompareAndSwap(false, true) {
+		return false
+	}
+	refreshCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(serverCtx, cancel)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		defer refreshing.Store(false)
+		defer stop()
+		defer cancel()
+		if _, err := apiClient.FetchArtists(refreshCtx); err != nil {
+			utils.Logger(refreshCtx).Error("Rafraîchissement du catalogue impossible", "error", err)
+		}
+	}()
+	return true
+}
+
+// EventsHandler diffuse en Server-Sent Events la progression des rafraîchissements du catalogue.
+// Événements : start, progress (artistes enrichis), done et failed ; les données sont en JSON.
+func EventsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.RenderError(w, r, http.StatusInternalServerError, "error.events_unsupported")
+		return
+	}
+
+	events, last := refreshEvents.subscribe()
+	defer refreshEvents.unsubscribe(events)
+
+	// Le flux reste ouvert au-delà du délai d'écriture du serveur
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		utils.Logger(r.Context()).Warn("Délai d'écriture du flux d'événements non levé", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Pas de mise en tampon par un proxy nginx
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if last.ID > 0 {
+		// Le client arrive en cours de route : lui donner l'état actuel
+		writeSSE(w, last)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return // Arrêt du serveur
+			}
+			if err := writeSSE(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE écrit une étape au format SSE (id, event, data)
+func writeSSE(w http.ResponseWriter, event refreshEvent) error {
+	data, err := json.Marshal(event.RefreshEvent)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Stage, data)
+	return err
+}
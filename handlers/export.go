@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"groupie-tracker-ng/utils"
+)
+
+// ExportHandler exporte la liste filtrée et triée des artistes (/artists/export?format=csv|json|ndjson).
+// Tous les paramètres de /artists sont pris en compte, sauf la pagination : l'export est complet.
+func ExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = utils.ExportCSV
+	}
+	contentType, ok := utils.ExportFormats[format]
+	if !ok {
+		utils.RenderError(w, r, http.StatusBadRequest, "error.invalid_export_format")
+		return
+	}
+
+	artists, err := apiClient.FetchArtists(r.Context())
+	if err != nil {
+		renderUpstreamError(w, r, err, "error.artists_unavailable")
+		return
+	}
+	listing := listArtists(artists, r.URL.Query())
+
+	filename := fmt.Sprintf("artistes-%s.%s", time.Now().Format("2006-01-02"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := utils.ExportArtists(w, format, listing.Artists); err != nil {
+		// En-têtes déjà envoyés : on ne peut que tronquer la réponse
+		utils.Logger(r.Context()).Error("Erreur lors de l'export", "format", format, "error", err)
+	}
+}
+
+// exportLink est un lien d'export de la liste courante
+type exportLink struct {
+	Label string
+	URL   string
+}
+
+// exportLinks construit les liens d'export de la liste courante : recherche, filtres
+// et tri sont recopiés, la pagination non
+func exportLinks(params url.Values) []exportLink {
+	query := url.Values{}
+	for key, values := range params {
+		if key != "page" && key != "perPage" {
+			query[key] = values
+		}
+	}
+
+	links := []exportLink{}
+	for _, format := range []string{utils.ExportCSV, utils.ExportJSON, utils.ExportNDJSON} {
+		query.Set("format", format)
+		links = append(links, exportLink{
+			Label: strings.ToUpper(format),
+			URL:   "/artists/export?" + query.Encode(),
+		})
+	}
+	return links
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// FLUX ATOM DES NOUVELLES SORTIES (/feeds/...)
+// ============================================
+
+// feedEntryLimit est le nombre d'entrées d'un flux
+const feedEntryLimit = 50
+
+// releaseStore mémorise les sorties vues par WatchReleases (ouvert par Configure)
+var releaseStore *utils.ReleaseStore
+
+// loadReleaseStore ouvre le registre ; illisible, il repart à vide plutôt que d'empêcher le démarrage
+func loadReleaseStore(path string) *utils.ReleaseStore {
+	store, err := utils.NewReleaseStore(path)
+	if err != nil {
+		log.Printf("⚠️ %v (registre repris à vide)", err)
+	}
+	return store
+}
+
+// WatchReleases relève les albums des artistes du catalogue dès le démarrage puis à chaque
+// intervalle, jusqu'à l'annulation de ctx. Les sorties jamais vues alimentent les flux.
+// Un relevé en cours à l'annulation s'arrête à l'étape suivante ; WatchReleases retourne ensuite.
+func WatchReleases(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Chaque relevé a son identifiant : ses appels Spotify se retrouvent ensemble dans les journaux
+		checkReleases(utils.WithRequestID(ctx, "releases-"+utils.NewRequestID()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReleases enregistre les albums actuels de chaque artiste du catalogue
+func checkReleases(ctx context.Context) {
+	artists, err := apiClient.FetchArtists(ctx)
+	if err != nil {
+		utils.Logger(ctx).Error("Relevé des sorties impossible", "error", err)
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	ids := make([]string, len(artists))
+	for i, artist := range artists {
+		ids[i] = artist.SpotifyID
+	}
+	albums, err := apiClient.NewLoader(ctx).Albums(ids)
+	if err != nil {
+		// Relevé partiel : les artistes en erreur seront repris au prochain passage
+		utils.Logger(ctx).Warn("Relevé des sorties incomplet", "error", err)
+	}
+
+	now := time.Now()
+	added := 0
+	for _, artist := range artists {
+		if list, ok := albums[artist.SpotifyID]; ok {
+			added += releaseStore.Record(artist, list, now)
+		}
+	}
+	if err := releaseStore.Save(); err != nil {
+		utils.Logger(ctx).Error("Erreur lors de la sauvegarde des sorties", "error", err)
+	}
+	if added > 0 {
+		utils.Logger(ctx).Info("📀 Nouvelles sorties enregistrées", "count", added)
+	}
+}
+
+// SaveState écrit sur disque l'état persistant (registre des sorties) ; appelée à l'arrêt
+func SaveState() error {
+	if releaseStore == nil {
+		return nil
+	}
+	return releaseStore.Save()
+}
+
+// ReleasesFeedHandler sert le flux de toutes les sorties (/feeds/releases.atom)
+func ReleasesFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+	serveFeed(w, r, utils.T(r, "feed.title"), "/artists", nil)
+}
+
+// ArtistFeedHandler sert le flux des sorties d'un artiste (/feeds/artist/{spotifyId}.atom).
+// L'adresse par ID du catalogue, qui change d'un rafraîchissement à l'autre, redirige vers l'ID Spotify.
+func ArtistFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+
+	spotifyID, ok := strings.CutSuffix(r.PathValue("file"), ".atom")
+	if !ok || spotifyID == "" {
+		utils.RenderError(w, r, http.StatusNotFound, "error.feed_not_found")
+		return
+	}
+
+	artists, err := apiClient.FetchArtists(r.Context())
+	if artistID, convErr := strconv.Atoi(spotifyID); convErr == nil {
+		if err != nil {
+			renderUpstreamError(w, r, err, "error.artists_unavailable")
+			return
+		}
+		for _, artist := range artists {
+			if artist.ID == artistID && artist.SpotifyID != "" {
+				// Redirection temporaire : l'ID du catalogue change à chaque rafraîchissement,
+				// seule l'adresse par ID Spotify peut être mémorisée par les lecteurs de flux
+				http.Redirect(w, r, artistFeedURL(artist), http.StatusFound)
+				return
+			}
+		}
+		utils.RenderError(w, r, http.StatusNotFound, "error.artist_not_found")
+		return
+	}
+
+	// Catalogue indisponible ou artiste sorti du catalogue : le registre suffit à servir le flux
+	keep := func(rel utils.Release) bool { return rel.SpotifyArtistID == spotifyID }
+	name, sitePath := "", "/artists"
+	for _, artist := range artists {
+		if artist.SpotifyID == spotifyID {
+			// Lien du flux par ID Spotify : les lecteurs le gardent au-delà du prochain rafraîchissement
+			name, sitePath = artist.Name, utils.ArtistPermalinkPath(spotifyID)
+			break
+		}
+	}
+	if latest := releaseStore.Latest(keep, 1); name == "" && len(latest) > 0 {
+		name = latest[0].ArtistName
+	}
+	if name == "" {
+		if err != nil {
+			renderUpstreamError(w, r, err, "error.artists_unavailable")
+			return
+		}
+		utils.RenderError(w, r, http.StatusNotFound, "error.artist_not_found")
+		return
+	}
+	serveFeed(w, r, utils.T(r, "feed.artist_title", name), sitePath, keep)
+}
+
+// artistFeedURL retourne l'adresse du flux des sorties d'un artiste, par son ID Spotify (vide sans ID)
+func artistFeedURL(artist models.Artist) string {
+	if artist.SpotifyID == "" {
+		return ""
+	}
+	return "/feeds/artist/" + url.PathEscape(artist.SpotifyID) + ".atom"
+}
+
+// GenreFeedHandler sert le flux des sorties d'un genre (/feeds/genre/{genre}.atom, ex. hip-hop.atom)
+func GenreFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+
+	genre, ok := strings.CutSuffix(r.PathValue("file"), ".atom")
+	folded := utils.FoldText(genre)
+	if !ok || folded == "" {
+		utils.RenderError(w, r, http.StatusNotFound, "error.feed_not_found")
+		return
+	}
+
+	serveFeed(w, r, utils.T(r, "feed.genre_title", genre),
+		"/artists?"+url.Values{"genre": {genre}}.Encode(),
+		func(rel utils.Release) bool {
+			for _, g := range rel.Genres {
+				if utils.FoldText(g) == folded {
+					return true
+				}
+			}
+			return false
+		})
+}
+
+// serveFeed encode le flux des sorties retenues ; If-Modified-Since est pris en charge
+// pour que les lecteurs de flux ne retéléchargent pas un flux inchangé
+func serveFeed(w http.ResponseWriter, r *http.Request, title, sitePath string, keep func(utils.Release) bool) {
+	base := requestBaseURL(r)
+	releases := releaseStore.Latest(keep, feedEntryLimit)
+	feed := utils.NewReleasesFeed(title, base+r.URL.Path, base+sitePath, releases)
+
+	var buf bytes.Buffer
+	if err := utils.WriteAtom(&buf, feed); err != nil {
+		utils.Logger(r.Context()).Error("Erreur lors de l'encodage du flux", "error", err)
+		utils.RenderError(w, r, http.StatusInternalServerError, "error.feed_failed")
+		return
+	}
+
+	var modified time.Time
+	if len(releases) > 0 {
+		modified = releases[0].FirstSeen
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	http.ServeContent(w, r, "", modified, bytes.NewReader(buf.Bytes()))
+}
+
+// requestBaseURL retourne l'origine de la requête (schéma et hôte), derrière un proxy compris
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
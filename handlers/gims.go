@@ -1,40 +1,32 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
-	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/api"
 	"groupie-tracker-ng/utils"
 )
 
 // GimsHandler gère la route spéciale "gims"
 func GimsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.RenderError(w, http.StatusMethodNotAllowed, "Méthode non autorisée")
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
 		return
 	}
 
-	// Rechercher l'artiste "GIMS" dans l'API
-	artist, err := apiClient.FindArtistByName("GIMS")
-	if err != nil {
-		// Essayer des variantes
-		variants := []string{"Gims", "Maître Gims", "Maitre Gims"}
-		var foundArtist *models.Artist
-
-		for _, variant := range variants {
-			foundArtist, err = apiClient.FindArtistByName(variant)
-			if err == nil {
-				break
-			}
-		}
-
-		if foundArtist == nil {
-			utils.RenderError(w, http.StatusNotFound, "Artiste GIMS non trouvé")
-			return
+	// Rechercher l'artiste "GIMS" dans l'API, puis ses variantes tant qu'il reste introuvable
+	artist, err := apiClient.FindArtistByName(r.Context(), "GIMS")
+	for _, variant := range []string{"Gims", "Maître Gims", "Maitre Gims"} {
+		if !errors.Is(err, api.ErrNotFound) {
+			break
 		}
-
-		artist = foundArtist
+		artist, err = apiClient.FindArtistByName(r.Context(), variant)
+	}
+	if err != nil {
+		renderUpstreamError(w, r, err, "error.gims_not_found")
+		return
 	}
 
 	// Rediriger vers la page de détails de GIMS
@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"groupie-tracker-ng/api"
+	"groupie-tracker-ng/graphql"
+	"groupie-tracker-ng/models"
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// GRAPHQL (/graphql)
+// ============================================
+
+const (
+	// maxGraphQLBody borne la taille du corps d'une requête GraphQL
+	maxGraphQLBody = 1 << 20
+	// maxGraphQLBatch borne le nombre de requêtes d'un lot ([{...}, {...}])
+	maxGraphQLBatch = 20
+	// maxGraphQLDepth borne l'imbrication des champs (artists > relatedArtists > artist > ...)
+	maxGraphQLDepth = 8
+	// maxGraphQLFields borne le nombre de champs d'une requête, fragments développés
+	maxGraphQLFields = 200
+)
+
+// gqlLoaderKey est la clé du Loader Spotify de la requête dans le contexte
+type gqlLoaderKey struct{}
+
+// gqlSchema est le schéma GraphQL : il reprend models.ArtistDetail, chaque niveau
+// (artistes -> albums -> titres) étant résolu en une fois pour tous ses parents
+var gqlSchema = graphql.NewSchema(
+	&graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.Field{
+			"artists": {
+				Type:        "[Artist]",
+				Args:        gqlFilterArgs(),
+				Description: "Artistes (mêmes recherche, filtres, tri et pagination que /artists)",
+				Resolve:     resolveArtists,
+			},
+			"artist": {
+				Type:        "Artist",
+				Args:        []graphql.Arg{{Name: "id", Type: "Int!", Description: "ID de l'artiste"}},
+				Description: "Artiste par ID (null s'il n'existe pas)",
+				Resolve:     resolveArtist,
+			},
+			"genres": {
+				Type:        "[Facet]",
+				Description: "Genres du catalogue et nombre d'artistes par genre",
+				Resolve:     resolveFacets(func(f utils.Facets) []utils.FacetCount { return f.Genres }),
+			},
+			"locations": {
+				Type:        "[Facet]",
+				Description: "Lieux de concerts et nombre d'artistes par lieu",
+				Resolve:     resolveFacets(func(f utils.Facets) []utils.FacetCount { return f.Locations }),
+			},
+		},
+	},
+	&graphql.Object{
+		Name:        "Artist",
+		Description: "Artiste du catalogue (models.ArtistDetail)",
+		Fields: map[string]*graphql.Field{
+			"id":             {Type: "Int"},
+			"name":           {Type: "String"},
+			"image":          {Type: "String"},
+			"members":        {Type: "[String]"},
+			"creationDate":   {Type: "Int"},
+			"firstAlbum":     {Type: "String"},
+			"firstAlbumDate": {Type: "String"},
+			"spotifyId":      {Type: "String"},
+			"spotifyUrl":     {Type: "String"},
+			"genres":         {Type: "[String]"},
+			"popularity":     {Type: "Int"},
+			"followers":      {Type: "Int"},
+			"country":        {Type: "String"},
+			"locations": {
+				Type:        "[String]",
+				Description: "Lieux de concerts",
+				Resolve:     resolveConcerts(func(c []gqlConcert) interface{} { return concertLocations(c) }),
+			},
+			"concertDates": {
+				Type:        "[String]",
+				Description: "Dates de concerts, tous lieux confondus",
+				Resolve:     resolveConcerts(func(c []gqlConcert) interface{} { return concertDates(c) }),
+			},
+			"relations": {
+				Type:        "[Concert]",
+				Description: "Dates de concerts par lieu",
+				Resolve:     resolveConcerts(func(c []gqlConcert) interface{} { return c }),
+			},
+			"topTracks": {
+				Type:        "[Track]",
+				Description: "Titres les plus écoutés",
+				Resolve:     bySpotifyID(artistSpotifyID, (*api.Loader).TopTracks),
+			},
+			"albums": {
+				Type:        "[Album]",
+				Description: "Albums (20 au plus)",
+				Resolve:     bySpotifyID(artistSpotifyID, (*api.Loader).Albums),
+			},
+			"relatedArtists": {
+				Type:        "[RelatedArtist]",
+				Description: "Artistes similaires",
+				Resolve:     bySpotifyID(artistSpotifyID, (*api.Loader).RelatedArtists),
+			},
+		},
+	},
+	&graphql.Object{
+		Name: "Album",
+		Fields: map[string]*graphql.Field{
+			"id":          {Type: "String"},
+			"name":        {Type: "String"},
+			"spotifyUrl":  {Type: "String"},
+			"releaseDate": {Type: "String"},
+			"imageUrl":    {Type: "String"},
+			"totalTracks": {Type: "Int"},
+			"tracks": {
+				Type:        "[Track]",
+				Description: "Titres de l'album (chargés par lots de 20 albums)",
+				Resolve: bySpotifyID(func(parent interface{}) string {
+					return parent.(models.AlbumInfo).ID
+				}, (*api.Loader).AlbumTracks),
+			},
+		},
+	},
+	&graphql.Object{
+		Name: "Track",
+		Fields: map[string]*graphql.Field{
+			"name":       {Type: "String"},
+			"spotifyUrl": {Type: "String"},
+			"albumName":  {Type: "String"},
+			"durationMs": {Type: "Int"},
+			"previewUrl": {Type: "String"},
+			"artists":    {Type: "[String]", Description: "Artistes crédités (featurings compris)"},
+		},
+	},
+	&graphql.Object{
+		Name: "RelatedArtist",
+		Fields: map[string]*graphql.Field{
+			"name":       {Type: "String"},
+			"imageUrl":   {Type: "String"},
+			"spotifyUrl": {Type: "String"},
+			"genres":     {Type: "[String]"},
+			"artist": {
+				Type:        "Artist",
+				Description: "Artiste du catalogue portant ce nom (null s'il n'y figure pas)",
+				Resolve:     resolveRelatedCatalogueArtist,
+			},
+		},
+	},
+	&graphql.Object{
+		Name: "Concert",
+		Fields: map[string]*graphql.Field{
+			"location": {Type: "String"},
+			"dates":    {Type: "[String]"},
+		},
+	},
+	&graphql.Object{
+		Name: "Facet",
+		Fields: map[string]*graphql.Field{
+			"name":  {Type: "String"},
+			"count": {Type: "Int"},
+		},
+	},
+).WithLimits(graphql.Limits{MaxDepth: maxGraphQLDepth, MaxFields: maxGraphQLFields})
+
+// GraphQLHandler exécute une requête GraphQL (GET ?query=... ou POST JSON) ou un lot de
+// requêtes (POST d'un tableau JSON). Les requêtes d'un même appel partagent le même Loader :
+// une ressource Spotify n'y est demandée qu'une fois.
+func GraphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var requests []graphql.Request
+	batch := false
+
+	switch r.Method {
+	case http.MethodGet:
+		params := r.URL.Query()
+		req := graphql.Request{Query: params.Get("query"), OperationName: params.Get("operationName")}
+		if vars := params.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				writeGraphQLError(w, r, http.StatusBadRequest, "error.graphql_variables")
+				return
+			}
+		}
+		requests = append(requests, req)
+	case http.MethodPost:
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxGraphQLBody))
+		if err != nil {
+			writeGraphQLError(w, r, http.StatusRequestEntityTooLarge, "error.body_too_large")
+			return
+		}
+		body = bytes.TrimSpace(body)
+		if batch = bytes.HasPrefix(body, []byte("[")); batch {
+			err = json.Unmarshal(body, &requests)
+		} else {
+			var req graphql.Request
+			err = json.Unmarshal(body, &req)
+			requests = append(requests, req)
+		}
+		if err != nil {
+			writeGraphQLError(w, r, http.StatusBadRequest, "error.body_invalid")
+			return
+		}
+		if batch && (len(requests) == 0 || len(requests) > maxGraphQLBatch) {
+			writeGraphQLError(w, r, http.StatusBadRequest, "error.batch_size", maxGraphQLBatch)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeGraphQLError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), gqlLoaderKey{}, apiClient.NewLoader(r.Context()))
+	responses := make([]*graphql.Response, len(requests))
+	for i, req := range requests {
+		responses[i] = gqlSchema.Execute(ctx, req)
+	}
+
+	if batch {
+		writeJSON(w, http.StatusOK, responses)
+		return
+	}
+	writeJSON(w, http.StatusOK, responses[0])
+}
+
+// GraphQLSchemaHandler sert le schéma GraphQL au format SDL
+func GraphQLSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeGraphQLError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, gqlSchema.SDL())
+}
+
+// writeGraphQLError écrit une erreur de transport au format GraphQL ({"errors": [...]}), traduite dans la langue de la requête
+func writeGraphQLError(w http.ResponseWriter, r *http.Request, status int, key string, args ...interface{}) {
+	writeJSON(w, status, graphql.Response{Errors: []*graphql.Error{{Message: utils.T(r, key, args...)}}})
+}
+
+// gqlLoader retourne le Loader Spotify de la requête
+func gqlLoader(ctx context.Context) *api.Loader {
+	if loader, ok := ctx.Value(gqlLoaderKey{}).(*api.Loader); ok {
+		return loader
+	}
+	return apiClient.NewLoader(ctx)
+}
+
+// gqlFilterArgs déclare comme arguments de Query.artists la recherche "q" et les paramètres de filtre
+func gqlFilterArgs() []graphql.Arg {
+	args := []graphql.Arg{{Name: "q", Type: "String", Description: "Recherche (même syntaxe que /search)"}}
+	for _, p := range utils.FilterParams {
+		t := "String"
+		if p.Type == "integer" {
+			t = "Int"
+		}
+		if p.Multi {
+			t = "[" + t + "]"
+		}
+		args = append(args, graphql.Arg{Name: p.Name, Type: t, Description: p.Description})
+	}
+	return args
+}
+
+// resolveArtists applique recherche, filtres, tri et pagination comme /artists
+func resolveArtists(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+	artists, err := apiClient.FetchArtists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de charger les artistes : %w", err)
+	}
+
+	// Les arguments GraphQL deviennent les paramètres d'URL compris par listArtists
+	params := url.Values{}
+	for name, value := range args {
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+		for _, item := range items {
+			if item != nil {
+				params.Add(name, fmt.Sprint(item))
+			}
+		}
+	}
+
+	listing := listArtists(artists, params)
+	page, _ := utils.Paginate(listing.Artists, listing.Options, nil)
+	values := []interface{}{page}
+
+	// Erreurs de la requête structurée : signalées sans empêcher le résultat
+	if len(listing.Parsed.Errors) > 0 {
+		messages := make([]string, len(listing.Parsed.Errors))
+		for i, qe := range listing.Parsed.Errors {
+			messages[i] = qe.Error()
+		}
+		return values, fmt.Errorf("requête partiellement interprétée : %s", strings.Join(messages, " ; "))
+	}
+	return values, nil
+}
+
+// resolveArtist retourne l'artiste du catalogue portant l'ID demandé
+func resolveArtist(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+	artists, err := apiClient.FetchArtists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de charger les artistes : %w", err)
+	}
+	id, _ := args["id"].(int)
+	for _, artist := range artists {
+		if artist.ID == id {
+			return []interface{}{artist}, nil
+		}
+	}
+	return []interface{}{nil}, nil
+}
+
+// resolveFacets retourne les compteurs de filtre choisis (genres ou lieux)
+func resolveFacets(pick func(utils.Facets) []utils.FacetCount) graphql.Resolver {
+	return func(ctx context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+		artists, err := apiClient.FetchArtists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("impossible de charger les artistes : %w", err)
+		}
+		facets := utils.ComputeFacets(artists, models.FilterOptions{}, loadLocationIndex())
+		return []interface{}{facetItems(pick(facets))}, nil
+	}
+}
+
+// artistSpotifyID retourne l'ID Spotify d'un artiste du catalogue
+func artistSpotifyID(parent interface{}) string {
+	return parent.(models.Artist).SpotifyID
+}
+
+// bySpotifyID crée un résolveur qui charge en une fois, via le Loader de la requête,
+// la ressource de tous les parents (identifiés par leur ID Spotify)
+func bySpotifyID[T any](key func(interface{}) string, load func(*api.Loader, []string) (map[string]T, error)) graphql.Resolver {
+	return func(ctx context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+		ids := make([]string, len(parents))
+		for i, parent := range parents {
+			ids[i] = key(parent)
+		}
+		found, err := load(gqlLoader(ctx), ids)
+		values := make([]interface{}, len(parents))
+		for i, id := range ids {
+			if value, ok := found[id]; ok {
+				values[i] = value
+			}
+		}
+		return values, err
+	}
+}
+
+// gqlConcert regroupe les dates de concerts d'un lieu
+type gqlConcert struct {
+	Location string   `json:"location"`
+	Dates    []string `json:"dates"`
+}
+
+// resolveConcerts lit les relations dates-lieux une fois pour tous les artistes parents (fichier des concerts)
+func resolveConcerts(project func([]gqlConcert) interface{}) graphql.Resolver {
+	return func(_ context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+		artists := make([]models.Artist, len(parents))
+		for i, parent := range parents {
+			artists[i] = parent.(models.Artist)
+		}
+		relations := apiClient.Relations(artists)
+		byArtist := make(map[int]map[string][]string, len(relations))
+		for _, rel := range relations {
+			byArtist[rel.ID] = rel.DatesLocations
+		}
+
+		values := make([]interface{}, len(parents))
+		for i, parent := range parents {
+			datesLocations := byArtist[parent.(models.Artist).ID]
+			concerts := make([]gqlConcert, 0, len(datesLocations))
+			for location, dates := range datesLocations {
+				concerts = append(concerts, gqlConcert{Location: location, Dates: dates})
+			}
+			sort.Slice(concerts, func(a, b int) bool { return concerts[a].Location < concerts[b].Location })
+			values[i] = project(concerts)
+		}
+		return values, nil
+	}
+}
+
+func concertLocations(concerts []gqlConcert) []string {
+	locations := make([]string, len(concerts))
+	for i, c := range concerts {
+		locations[i] = c.Location
+	}
+	return locations
+}
+
+func concertDates(concerts []gqlConcert) []string {
+	dates := []string{}
+	for _, c := range concerts {
+		dates = append(dates, c.Dates...)
+	}
+	return dates
+}
+
+// resolveRelatedCatalogueArtist relie un artiste similaire à l'artiste du catalogue de même nom
+func resolveRelatedCatalogueArtist(ctx context.Context, parents []interface{}, _ map[string]interface{}) ([]interface{}, error) {
+	artists, err := apiClient.FetchArtists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de charger les artistes : %w", err)
+	}
+	byName := make(map[string]models.Artist, len(artists))
+	for _, artist := range artists {
+		byName[utils.FoldText(artist.Name)] = artist
+	}
+
+	values := make([]interface{}, len(parents))
+	for i, parent := range parents {
+		if artist, ok := byName[utils.FoldText(parent.(models.RelatedArtistInfo).Name)]; ok {
+			values[i] = artist
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"groupie-tracker-ng/api"
+)
+
+// ============================================
+// SONDES DE L'ORCHESTRATEUR (/healthz, /readyz)
+// ============================================
+
+// États de disponibilité rapportés par /readyz
+const (
+	readinessReady    = "ready"
+	readinessDegraded = "degraded"  // Catalogue servi depuis le cache, Spotify en échec
+	readinessNotReady = "not_ready" // Aucun catalogue, ou identifiants Spotify refusés
+)
+
+// ReadinessResponse est la réponse de /readyz
+type ReadinessResponse struct {
+	Status    string          `json:"status"`
+	Catalogue CatalogueStatus `json:"catalogue"`
+	Token     TokenStatus     `json:"token"`
+	Refresh   RefreshStatus   `json:"refresh"`
+	Source    string          `json:"source"` // État de Spotify : ok, unknown, unavailable, unauthenticated
+}
+
+// CatalogueStatus décrit le catalogue en cache
+type CatalogueStatus struct {
+	Loaded   bool       `json:"loaded"`
+	Size     int        `json:"size"`
+	LoadedAt *time.Time `json:"loadedAt,omitempty"`
+	Fresh    bool       `json:"fresh"` // Encore dans sa durée de validité
+}
+
+// TokenStatus décrit le token d'accès Spotify
+type TokenStatus struct {
+	Valid     bool       `json:"valid"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Error     string     `json:"error,omitempty"` // Échec de la dernière demande de token
+}
+
+// RefreshStatus décrit les derniers rafraîchissements du catalogue
+type RefreshStatus struct {
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure *time.Time `json:"lastFailure,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// HealthzHandler répond tant que le processus sert des requêtes (/healthz) ; aucune dépendance n'est vérifiée
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireProbeMethod(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ReadyzHandler indique si l'instance peut recevoir du trafic (/readyz) : 200 si un catalogue est
+// chargé et que les identifiants Spotify ne sont pas refusés, 503 sinon. Spotify indisponible avec
+// un catalogue en cache donne "degraded" (200) : les pages restent servies depuis le cache.
+// La sonde n'appelle pas Spotify ; elle rapporte le résultat des derniers appels.
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireProbeMethod(w, r) {
+		return
+	}
+
+	now := time.Now()
+	st := apiClient.Status()
+	resp := ReadinessResponse{
+		Catalogue: CatalogueStatus{
+			Loaded:   st.CatalogueLoaded(),
+			Size:     st.CatalogueSize,
+			LoadedAt: optionalTime(st.CatalogueTime),
+			Fresh:    st.CatalogueFresh(now),
+		},
+		Token: TokenStatus{
+			Valid:     st.TokenValid(now),
+			ExpiresAt: optionalTime(st.TokenExpiry),
+		},
+		Refresh: RefreshStatus{
+			LastSuccess: optionalTime(st.LastRefresh),
+			LastFailure: optionalTime(st.LastFailure),
+		},
+		Source: st.Source(),
+	}
+	if st.AuthError != nil {
+		resp.Token.Error = st.AuthError.Error()
+	}
+	if st.LastError != nil && st.LastFailure.After(st.LastRefresh) {
+		resp.Refresh.LastError = st.LastError.Error()
+	}
+
+	status := http.StatusOK
+	switch {
+	case !resp.Catalogue.Loaded || resp.Source == api.SourceUnauthenticated:
+		resp.Status = readinessNotReady
+		status = http.StatusServiceUnavailable
+	case resp.Source != api.SourceOK:
+		resp.Status = readinessDegraded
+	default:
+		resp.Status = readinessReady
+	}
+	writeJSON(w, status, resp)
+}
+
+// requireProbeMethod n'accepte que GET et HEAD ; les sondes ne sont jamais mises en cache
+func requireProbeMethod(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeAPIError(w, r, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "error.method_not_allowed")
+		return false
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	return true
+}
+
+// optionalTime retourne nil pour une date nulle (champ omis en JSON)
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -9,19 +9,19 @@ import (
 func HomeHandler(w http.ResponseWriter, r *http.Request) {
 	// Vérifier que la méthode HTTP est GET
 	if r.Method != http.MethodGet {
-		utils.RenderError(w, http.StatusMethodNotAllowed, "Méthode non autorisée")
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
 		return
 	}
 
 	// Vérifier que l'URL est exactement "/"
 	if r.URL.Path != "/" {
-		utils.RenderError(w, http.StatusNotFound, "Page non trouvée")
+		utils.RenderError(w, r, http.StatusNotFound, "error.page_not_found")
 		return
 	}
 
 	data := map[string]interface{}{
-		"Title": "Accueil",
+		"Title": utils.T(r, "home.title"),
 	}
 
-	renderTemplate(w, "home.html", data)
+	renderTemplate(w, r, "home.html", data)
 }
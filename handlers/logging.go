@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"groupie-tracker-ng/utils"
+)
+
+// statusRecorder retient le statut et la taille de la réponse pour le journal
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush transmet les données en attente (flux SSE de /events)
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		flusher.Flush()
+	}
+}
+
+// Unwrap donne accès à la réponse d'origine (http.ResponseController)
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// WithRequestLog attribue un identifiant à chaque requête (repris de X-Request-ID s'il est valide),
+// le place dans son contexte et dans la réponse, puis journalise la requête en JSON :
+// méthode, chemin, statut, octets envoyés et durée
+func WithRequestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(utils.RequestIDHeader)
+		if !utils.ValidRequestID(id) {
+			id = utils.NewRequestID()
+		}
+		w.Header().Set(utils.RequestIDHeader, id)
+		ctx := utils.WithRequestID(r.Context(), id)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK // Réponse vide
+		}
+		level := slog.LevelInfo
+		if rec.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		utils.Logger(ctx).Log(ctx, level, "requête",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"groupie-tracker-ng/metrics"
+)
+
+// Métriques des requêtes HTTP servies (publiées sur /metrics)
+var (
+	httpRequests = metrics.NewCounterVec("groupie_http_requests_total",
+		"Requêtes HTTP servies par route, méthode et statut.",
+		"route", "method", "status")
+	httpDuration = metrics.NewHistogramVec("groupie_http_request_duration_seconds",
+		"Durée des requêtes HTTP par route (jusqu'à la fin de la réponse).",
+		nil, "route")
+	_ = metrics.NewGaugeFunc("groupie_catalogue_artists",
+		"Nombre d'artistes du catalogue en cache (0 tant qu'il n'est pas chargé).",
+		func() float64 {
+			artists, _ := apiClient.CachedArtists()
+			return float64(len(artists))
+		})
+)
+
+// MetricsHandler publie les métriques au format texte de Prometheus (/metrics)
+var MetricsHandler = metrics.Default.Handler()
+
+// metricMethods sont les méthodes comptées sous leur nom ; les autres sont regroupées sous "other"
+var metricMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// WithMetrics compte les requêtes et mesure leur durée par route. La route est le motif
+// enregistré dans mux ("/artist/{id}/concerts.ics") et non le chemin : le nombre de séries reste borné.
+func WithMetrics(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		method := r.Method
+		if !metricMethods[method] {
+			method = "other"
+		}
+
+		rec := &statusRecorder{ResponseWriter: w}
+		start := time.Now()
+		mux.ServeHTTP(rec, r)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		httpRequests.Inc(route, method, strconv.Itoa(rec.status))
+		httpDuration.Observe(time.Since(start).Seconds(), route)
+	})
+}
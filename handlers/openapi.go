@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"groupie-tracker-ng/utils"
+)
+
+// ============================================
+// DOCUMENT OPENAPI (/api/openapi.json)
+// ============================================
+
+// Le document est généré à partir de APIRoutes et des types déclarés pour chaque
+// route (Data, Meta). Rien n'oblige un handler à encoder le type déclaré :
+// TestAPIRoutesMatchOpenAPI vérifie les réponses réelles contre le document.
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  map[string]interface{}
+)
+
+// OpenAPIHandler sert le contrat OpenAPI 3 de l'API JSON
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+	openAPIOnce.Do(func() {
+		openAPIDoc = BuildOpenAPI(APIRoutes)
+	})
+	writeJSON(w, http.StatusOK, openAPIDoc)
+}
+
+// BuildOpenAPI construit le document OpenAPI 3 décrivant les routes données
+func BuildOpenAPI(routes []APIRoute) map[string]interface{} {
+	g := &schemaGenerator{components: map[string]interface{}{}}
+	errorSchema := g.schemaFor(reflect.TypeOf(APIErrorResponse{}))
+
+	paths := map[string]interface{}{}
+	for _, route := range routes {
+		envelope := map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"data": g.schemaFor(reflect.TypeOf(route.Data))},
+			"required":   []string{"data"},
+		}
+		if route.Meta != nil {
+			envelope["properties"].(map[string]interface{})["meta"] = g.schemaFor(reflect.TypeOf(route.Meta))
+			envelope["required"] = []string{"data", "meta"}
+		}
+
+		responses := map[string]interface{}{
+			"200": jsonResponse("Succès", envelope),
+			"405": jsonResponse(http.StatusText(http.StatusMethodNotAllowed), errorSchema),
+		}
+		for _, status := range route.Errors {
+			responses[strconv.Itoa(status)] = jsonResponse(http.StatusText(status), errorSchema)
+		}
+
+		parameters := []interface{}{}
+		for _, p := range route.PathParams {
+			parameters = append(parameters, openAPIParameter(p, "path"))
+		}
+		for _, p := range route.QueryParams {
+			parameters = append(parameters, openAPIParameter(p, "query"))
+		}
+
+		paths[route.Pattern] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": operationID(route.Pattern),
+				"summary":     route.Summary,
+				"parameters":  parameters,
+				"responses":   responses,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Groupie Tracker API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.components,
+		},
+	}
+}
+
+// jsonResponse décrit une réponse application/json
+func jsonResponse(description string, schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// openAPIParameter convertit un paramètre documenté en paramètre OpenAPI
+func openAPIParameter(p utils.FilterParam, in string) map[string]interface{} {
+	schema := map[string]interface{}{"type": p.Type}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	param := map[string]interface{}{
+		"name":        p.Name,
+		"in":          in,
+		"description": p.Description,
+		"required":    in == "path",
+		"schema":      schema,
+	}
+	if p.Multi {
+		param["schema"] = map[string]interface{}{"type": "array", "items": schema}
+		param["explode"] = true
+	}
+	return param
+}
+
+var nonAlnum = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// operationID dérive un identifiant stable du motif ("/api/v1/artists/{id}" -> "getArtistsById")
+func operationID(pattern string) string {
+	id := "get"
+	for _, part := range strings.Split(strings.TrimPrefix(pattern, "/api/v1/"), "/") {
+		if strings.HasPrefix(part, "{") {
+			part = "by " + strings.Trim(part, "{}")
+		}
+		for _, word := range nonAlnum.Split(part, -1) {
+			if word != "" {
+				id += strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+	}
+	return id
+}
+
+// schemaGenerator produit les schémas JSON à partir des types Go, selon les règles
+// d'encoding/json ; les structs nommées vont dans components/schemas
+type schemaGenerator struct {
+	components map[string]interface{}
+}
+
+// schemaFor retourne le schéma d'un type
+func (g *schemaGenerator) schemaFor(t reflect.Type) interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = nil // Réservé avant la descente (types récursifs)
+			g.components[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		// interface{} : n'importe quelle valeur JSON
+		return map[string]interface{}{}
+	}
+}
+
+// jsonField est un champ tel qu'encoding/json l'encode
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+	depth     int
+}
+
+// structSchema décrit une struct ; les champs sans omitempty sont requis
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, f := range jsonFields(t, 0) {
+		properties[f.name] = g.schemaFor(f.typ)
+		if !f.omitEmpty {
+			required = append(required, f.name)
+		}
+	}
+	sort.Strings(required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonFields liste les champs encodés d'une struct, structs embarquées aplaties
+// (à nom égal, le champ le moins profond l'emporte, comme dans encoding/json)
+func jsonFields(t reflect.Type, depth int) []jsonField {
+	byName := map[string]jsonField{}
+	order := []string{}
+	keep := func(f jsonField) {
+		if prev, ok := byName[f.name]; ok && prev.depth <= f.depth {
+			return
+		} else if !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = f
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, f := range jsonFields(ft, depth+1) {
+					keep(f)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		keep(jsonField{
+			name:      name,
+			typ:       sf.Type,
+			omitEmpty: strings.Contains(opts, "omitempty"),
+			depth:     depth,
+		})
+	}
+
+	fields := make([]jsonField, 0, len(order))
+	for _, name := range order {
+		fields = append(fields, byName[name])
+	}
+	return fields
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"groupie-tracker-ng/api"
+	"groupie-tracker-ng/utils"
+)
+
+// fakeSpotify répond aux appels du client Spotify avec un petit catalogue fixe.
+// Les champs absents (genres, images) reproduisent les réponses réelles les plus pauvres.
+type fakeSpotify struct{}
+
+var fakeSpotifyArtists = []map[string]interface{}{
+	{"id": "1dfeR4HaWDbWqFHLkxsg1d", "name": "Queen", "genres": []string{"rock", "glam rock"}, "popularity": 85,
+		"followers": map[string]int{"total": 50000000}, "external_urls": map[string]string{"spotify": "https://open.spotify.com/artist/1dfeR4HaWDbWqFHLkxsg1d"},
+		"images": []map[string]string{{"url": "https://i.scdn.co/image/queen"}}},
+	{"id": "4tZwfgrHOc3mvqYlEYSvVi", "name": "Daft Punk", "popularity": 78,
+		"followers": map[string]int{"total": 9000000}, "external_urls": map[string]string{"spotify": "https://open.spotify.com/artist/4tZwfgrHOc3mvqYlEYSvVi"}},
+}
+
+func (fakeSpotify) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1")
+	var body interface{}
+	switch {
+	case req.URL.Host == "accounts.spotify.com":
+		body = map[string]interface{}{"access_token": "test", "token_type": "Bearer", "expires_in": 3600}
+	case path == "/search":
+		// Recherche par nom exact : cet artiste seul ; sinon tout le catalogue
+		items := fakeSpotifyArtists
+		for _, artist := range fakeSpotifyArtists {
+			if artist["name"] == req.URL.Query().Get("q") {
+				items = []map[string]interface{}{artist}
+			}
+		}
+		body = map[string]interface{}{"artists": map[string]interface{}{"items": items, "total": len(items)}}
+	case strings.HasSuffix(path, "/albums"):
+		body = map[string]interface{}{"items": []map[string]interface{}{
+			{"id": "album1", "name": "First Album", "release_date": "1973-07-13", "total_tracks": 10},
+		}}
+	case strings.HasSuffix(path, "/top-tracks"):
+		body = map[string]interface{}{"tracks": []map[string]interface{}{
+			{"id": "track1", "name": "Track", "duration_ms": 180000, "album": map[string]string{"name": "First Album"}},
+		}}
+	case strings.HasSuffix(path, "/related-artists"):
+		body = map[string]interface{}{"artists": []map[string]interface{}{{"id": "related1", "name": "Related"}}}
+	case strings.HasPrefix(path, "/artists/"):
+		id := strings.TrimPrefix(path, "/artists/")
+		for _, artist := range fakeSpotifyArtists {
+			if artist["id"] == id {
+				body = artist
+			}
+		}
+	}
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Request: req}
+	if body == nil {
+		resp.StatusCode = http.StatusNotFound
+		body = map[string]interface{}{"error": map[string]interface{}{"status": 404, "message": "not found"}}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(strings.NewReader(string(data)))
+	return resp, nil
+}
+
+// TestAPIRoutesMatchOpenAPI appelle chaque route de l'API JSON et vérifie la réponse
+// contre le schéma que BuildOpenAPI publie pour son statut
+func TestAPIRoutesMatchOpenAPI(t *testing.T) {
+	messages, err := loadMessages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	utils.Messages = messages
+	apiClient.Configure(api.Settings{ClientID: "test", ClientSecret: "test", Transport: fakeSpotify{}})
+
+	doc := BuildOpenAPI(APIRoutes)
+	// Aller-retour JSON : le document est validé tel que les clients le lisent
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	for _, route := range APIRoutes {
+		mux.HandleFunc(route.Pattern, route.Handler)
+	}
+
+	requests := map[string][]string{
+		"/api/v1/artists":      {"/api/v1/artists", "/api/v1/artists?q=genre:rock&sort=name", "/api/v1/artists?q=nothing+matches"},
+		"/api/v1/artists/{id}": {"/api/v1/artists/1", "/api/v1/artists/2", "/api/v1/artists/999", "/api/v1/artists/abc"},
+		"/api/v1/genres":       {"/api/v1/genres"},
+		"/api/v1/locations":    {"/api/v1/locations"},
+	}
+	for _, route := range APIRoutes {
+		targets := requests[route.Pattern]
+		if len(targets) == 0 {
+			t.Errorf("%s : aucune requête de test", route.Pattern)
+			continue
+		}
+		operation := spec["paths"].(map[string]interface{})[route.Pattern].(map[string]interface{})["get"].(map[string]interface{})
+		responses := operation["responses"].(map[string]interface{})
+
+		for _, target := range append(targets, "POST "+targets[0]) {
+			method := http.MethodGet
+			if m, path, ok := strings.Cut(target, " "); ok {
+				method, target = m, path
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+
+			response, ok := responses[strconv.Itoa(w.Code)].(map[string]interface{})
+			if !ok {
+				t.Errorf("%s %s : statut %d non documenté", method, target, w.Code)
+				continue
+			}
+			schema := response["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+			var body interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Errorf("%s %s : réponse non JSON : %v", method, target, err)
+				continue
+			}
+			for _, problem := range checkSchema(spec, schema, body, "$") {
+				t.Errorf("%s %s (%d) : %s", method, target, w.Code, problem)
+			}
+		}
+	}
+}
+
+// checkSchema liste les écarts entre une valeur JSON décodée et un schéma du document OpenAPI
+func checkSchema(spec map[string]interface{}, schema, value interface{}, path string) []string {
+	s, _ := schema.(map[string]interface{})
+	if ref, ok := s["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		s = spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})[name].(map[string]interface{})
+	}
+	typ, _ := s["type"].(string)
+	if typ == "" {
+		return nil // Schéma libre : toute valeur JSON
+	}
+
+	var problems []string
+	switch typ {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s : objet attendu, %s reçu", path, jsonKind(value))}
+		}
+		required, _ := s["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s : champ requis absent", path, name))
+			}
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		for name, field := range obj {
+			if sub, ok := properties[name]; ok {
+				problems = append(problems, checkSchema(spec, sub, field, path+"."+name)...)
+			} else if extra, ok := s["additionalProperties"]; ok {
+				problems = append(problems, checkSchema(spec, extra, field, path+"."+name)...)
+			} else {
+				problems = append(problems, fmt.Sprintf("%s.%s : champ non documenté", path, name))
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s : tableau attendu, %s reçu", path, jsonKind(value))}
+		}
+		for i, item := range items {
+			problems = append(problems, checkSchema(spec, s["items"], item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			problems = append(problems, fmt.Sprintf("%s : entier attendu, %s reçu", path, jsonKind(value)))
+		}
+	case "number", "string", "boolean":
+		if jsonKind(value) != typ {
+			problems = append(problems, fmt.Sprintf("%s : %s attendu, %s reçu", path, typ, jsonKind(value)))
+		}
+	}
+	return problems
+}
+
+// jsonKind nomme le type JSON d'une valeur décodée
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
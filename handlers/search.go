@@ -4,15 +4,34 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 
-	"groupie-tracker-ng/models"
 	"groupie-tracker-ng/utils"
 )
 
+// maxSuggestLimit borne le paramètre "limit" de /suggestions
+const maxSuggestLimit = 50
+
+// rankingWeights sont les pondérations du classement des suggestions (configuration, voir Configure)
+var rankingWeights atomic.Pointer[utils.RankingWeights]
+
+// setRankingWeights remplace les pondérations du classement des suggestions
+func setRankingWeights(w utils.RankingWeights) {
+	rankingWeights.Store(&w)
+}
+
+// suggestWeights retourne les pondérations configurées (par défaut avant Configure)
+func suggestWeights() utils.RankingWeights {
+	if w := rankingWeights.Load(); w != nil {
+		return *w
+	}
+	return utils.DefaultRankingWeights
+}
+
 // SearchHandler gère la recherche d'artistes (même formulaire de filtres que /artists)
 func SearchHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.RenderError(w, http.StatusMethodNotAllowed, "Méthode non autorisée")
+		utils.RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
 		return
 	}
 
@@ -22,110 +41,87 @@ func SearchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if len(query) < 1 || len(query) > 100 {
-		utils.RenderError(w, http.StatusBadRequest, "Requête de recherche invalide (1-100 caractères)")
+		utils.RenderError(w, r, http.StatusBadRequest, "error.invalid_search")
 		return
 	}
 
-	artists, err := apiClient.FetchArtists()
-	apiError := ""
+	artists, err := apiClient.FetchArtists(r.Context())
 	if err != nil {
-		artists = []models.Artist{}
-		apiError = err.Error()
+		renderUpstreamError(w, r, err, "error.artists_unavailable")
+		return
 	}
 
-	filteredArtists := utils.SearchArtists(artists, query)
-	filterOptions := utils.ParseFilterOptions(r.URL.Query())
-	filteredArtists = utils.FilterArtists(filteredArtists, filterOptions)
-
-	// Récupérer la liste des lieux populaires pour le filtre
-	locationsList := utils.GetPopularLocations()
-	minYear := ""
-	maxYear := ""
-	if filterOptions.MinYear > 0 {
-		minYear = strconv.Itoa(filterOptions.MinYear)
-	}
-	if filterOptions.MaxYear > 0 {
-		maxYear = strconv.Itoa(filterOptions.MaxYear)
-	}
-	firstAlbumMin := filterOptions.FirstAlbumMin
-	firstAlbumMax := filterOptions.FirstAlbumMax
-	memberSelected := make(map[int]bool)
-	for _, mc := range filterOptions.MemberCount {
-		memberSelected[mc] = true
-	}
-	locationSelected := make(map[string]bool)
-	for _, loc := range filterOptions.Locations {
-		locationSelected[loc] = true
-	}
+	// Requête structurée, filtres et tri, puis pagination
+	listing := listArtists(artists, r.URL.Query())
+	page, pagination := utils.Paginate(listing.Artists, listing.Options, r.URL)
 
 	data := map[string]interface{}{
-		"Title":            "Résultats pour « " + query + " »",
-		"Artists":          filteredArtists,
-		"Query":            query,
-		"Locations":        locationsList,
-		"MinYear":          minYear,
-		"MaxYear":          maxYear,
-		"FirstAlbumMin":    firstAlbumMin,
-		"FirstAlbumMax":    firstAlbumMax,
-		"Member1":          memberSelected[1],
-		"Member2":          memberSelected[2],
-		"Member3":          memberSelected[3],
-		"Member4":          memberSelected[4],
-		"Member5":          memberSelected[5],
-		"LocationSelected": locationSelected,
-		"APIError":         apiError,
+		"Title":       utils.T(r, "search.title", query),
+		"Artists":     page,
+		"Query":       query,
+		"QueryErrors": listing.Parsed.Errors,
 	}
+	addFilterData(data, listing.Options, listing.facets())
+	data["Pagination"] = pagination
 
-	renderTemplate(w, "artists.html", data)
+	renderTemplate(w, r, "artists.html", data)
 }
 
 // SuggestionsHandler retourne des suggestions JSON pour la barre de recherche
 func SuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Les messages sont traduits avant WriteHeader, qui fige l'en-tête Vary
 	if r.Method != http.MethodGet {
+		message := utils.T(r, "error.method_not_allowed")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Méthode non autorisée"})
+		json.NewEncoder(w).Encode(map[string]string{"error": message})
 		return
 	}
 
 	query := r.URL.Query().Get("q")
 	if query == "" {
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]models.Artist{})
+		json.NewEncoder(w).Encode([]utils.Suggestion{})
 		return
 	}
 
 	// Valider la longueur de la requête
 	if len(query) > 100 {
+		message := utils.T(r, "error.query_too_long")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Requête trop longue"})
+		json.NewEncoder(w).Encode(map[string]string{"error": message})
 		return
 	}
 
-	// Récupérer tous les artistes
-	artists, err := apiClient.FetchArtists()
+	// Index de recherche, lieux de concerts compris (reconstruit à chaque rafraîchissement du catalogue)
+	index, err := currentSearchIndex(r.Context())
 	if err != nil {
+		f := classifyUpstream(err, "error.artists_unavailable")
+		f.prepare(w, r, err)
+		message := utils.T(r, f.key)
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]models.Artist{})
+		w.WriteHeader(f.status)
+		json.NewEncoder(w).Encode(map[string]string{"error": message})
 		return
 	}
 
-	// Générer les suggestions
-	suggestions := utils.GetSuggestions(artists, query)
-
-	// Convertir en format simple pour le frontend
-	type Suggestion struct {
-		Name string `json:"name"`
-		ID   int    `json:"id"`
+	// Nombre de suggestions (paramètre "limit", borné)
+	limit := utils.DefaultSuggestLimit
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = min(l, maxSuggestLimit)
 	}
 
-	result := make([]Suggestion, len(suggestions))
-	for i, artist := range suggestions {
-		result[i] = Suggestion{
-			Name: artist.Name,
-			ID:   artist.ID,
-		}
+	// Générer les suggestions typées (artiste, membre, album, genre, lieu, années), classées par note
+	result := index.Suggest(query, utils.SuggestOptions{
+		Limit:   limit,
+		Weights: suggestWeights(),
+	})
+
+	// Libellés des types dans la langue de la requête ("Freddie Mercury — member")
+	lang := utils.Language(r)
+	for i := range result {
+		result[i].Localize(lang)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
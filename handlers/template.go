@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
-	"log"
+	"io/fs"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
+
+	"groupie-tracker-ng/locales"
+	"groupie-tracker-ng/static"
+	"groupie-tracker-ng/templates"
+	"groupie-tracker-ng/utils"
 )
 
 // formatDuration convertit des millisecondes en "m:ss"
@@ -19,39 +26,150 @@ func formatDuration(ms int) string {
 	return fmt.Sprintf("%d:%02d", sec/60, sec%60)
 }
 
-// formatNumber formate un nombre avec des séparateurs de milliers
-func formatNumber(n int) string {
-	if n < 1000 {
-		return fmt.Sprintf("%d", n)
+// templateFuncs retourne les fonctions disponibles dans les templates d'une langue
+// (join + urlpath pour les listes et liens ; t, formatNumber, relativeTime et lang liés à la langue)
+func templateFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"join":           strings.Join,
+		"urlpath":        url.PathEscape,
+		"formatDuration": formatDuration,
+		"asset":          assetURL,
+		"lang":           func() string { return lang },
+		"t": func(key string, args ...interface{}) string {
+			return utils.Messages.T(lang, key, args...)
+		},
+		"formatNumber": func(n int) string {
+			return utils.Messages.FormatNumber(lang, n)
+		},
+		"relativeTime": func(t time.Time) string {
+			return relativeTime(lang, t)
+		},
 	}
-	if n < 1000000 {
-		return fmt.Sprintf("%.1fK", float64(n)/1000)
+}
+
+// relativeTime situe une date par rapport à maintenant ("il y a 3m12s", "dans 58m0s", "jamais" si nulle)
+func relativeTime(lang string, t time.Time) string {
+	if t.IsZero() {
+		return utils.Messages.T(lang, "time.never")
+	}
+	d := time.Until(t).Round(time.Second)
+	if d < 0 {
+		return utils.Messages.T(lang, "time.ago", (-d).String())
 	}
-	return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	return utils.Messages.T(lang, "time.in", d.String())
 }
 
-func renderTemplate(w http.ResponseWriter, tmpl string, data interface{}) {
-	funcMap := template.FuncMap{
-		"join":           strings.Join,
-		"urlpath":        url.PathEscape,
-		"formatDuration": formatDuration,
-		"formatNumber":   formatNumber,
+// languageLink est un lien vers la page courante dans une autre langue
+type languageLink struct {
+	Lang    string
+	Label   string // Nom de la langue dans cette langue ("English")
+	URL     string
+	Current bool
+}
+
+// languageLinks retourne les liens vers la page courante dans chaque langue (?lang=)
+func languageLinks(r *http.Request) []languageLink {
+	current := utils.Language(r)
+	languages := utils.Messages.Languages()
+	links := make([]languageLink, len(languages))
+	for i, lang := range languages {
+		query := r.URL.Query()
+		query.Set(utils.LanguageParam, lang)
+		links[i] = languageLink{
+			Lang:    lang,
+			Label:   utils.Messages.T(lang, "language.name"),
+			URL:     r.URL.Path + "?" + query.Encode(),
+			Current: lang == current,
+		}
 	}
-	// 1. Parser les templates (join + urlpath pour les listes et liens)
-	templates, err := template.New("").Funcs(funcMap).ParseFiles(
-		"templates/layout.html",
-		"templates/"+tmpl,
-	)
+	return links
+}
+
+// WithLanguage négocie la langue de chaque requête (?lang=, cookie, Accept-Language) et la
+// place dans son contexte ; une langue choisie par ?lang= est mémorisée dans un cookie.
+// Vary n'est ajouté qu'aux réponses traduites (voir utils.WithLocalizedResponse), jamais aux
+// fichiers statiques : leurs pages d'erreur suivent la langue, mais ils restent cachables.
+func WithLanguage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := utils.Messages.Negotiate(r)
+		if requested := r.URL.Query().Get(utils.LanguageParam); requested != "" && requested == lang {
+			http.SetCookie(w, &http.Cookie{
+				Name:     utils.LanguageCookie,
+				Value:    lang,
+				Path:     "/",
+				MaxAge:   365 * 24 * 60 * 60,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		ctx := utils.WithLocalizedResponse(r.Context(), lang, w)
+		if strings.HasPrefix(r.URL.Path, "/static/") {
+			ctx = utils.WithLanguage(r.Context(), lang)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// loadMessages charge les catalogues de messages embarqués
+func loadMessages() (*utils.Catalog, error) {
+	return utils.LoadCatalog(locales.FS)
+}
+
+// assets sert les fichiers statiques (installé par Configure)
+var assets *utils.AssetServer
+
+// assetURL retourne l'URL d'un fichier statique, avec son empreinte ("css/style.css")
+func assetURL(name string) string {
+	if assets == nil {
+		return "/static/" + name
+	}
+	return assets.URL(name)
+}
+
+// loadAssets prépare les fichiers statiques embarqués, ou ceux de dir s'il est défini
+func loadAssets(dir string, dev bool) (*utils.AssetServer, error) {
+	var fsys fs.FS = static.FS
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	return utils.NewAssetServer(fsys, "/static/", dev)
+}
+
+// StaticHandler sert les fichiers statiques (/static/...)
+func StaticHandler(w http.ResponseWriter, r *http.Request) {
+	if assets == nil {
+		utils.RenderError(w, r, http.StatusNotFound, "error.file_not_found")
+		return
+	}
+	assets.ServeHTTP(w, r)
+}
+
+// loadTemplates compile les templates embarqués, ou ceux de dir s'il est défini.
+// En mode développement, ils sont relus depuis le disque dès qu'un fichier change.
+func loadTemplates(dir string, dev bool) (*utils.TemplateSet, error) {
+	var fsys fs.FS = templates.FS
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	return utils.NewTemplateSet(fsys, templateFuncs, utils.Messages.Languages(), dev)
+}
+
+func renderTemplate(w http.ResponseWriter, r *http.Request, tmpl string, data map[string]interface{}) {
+	// 1. Récupérer la page compilée dans la langue de la requête (recompilée si besoin en mode développement)
+	lang := utils.Language(r)
+	templates, err := utils.Templates.Lookup(lang, tmpl)
 	if err != nil {
-		log.Printf("Erreur lors du parsing du template %s: %v", tmpl, err)
+		utils.Logger(r.Context()).Error("Erreur lors du chargement du template", "template", tmpl, "error", err)
 		http.Error(w, "Erreur interne du serveur - Template non trouvé", http.StatusInternalServerError)
 		return
 	}
 
+	data["LangLinks"] = languageLinks(r)
+
 	// 2. Utiliser un buffer pour préparer le rendu en mémoire
 	buf := new(bytes.Buffer)
 	if err := templates.ExecuteTemplate(buf, "layout", data); err != nil {
-		log.Printf("Erreur lors du rendu du template %s: %v", tmpl, err)
+		utils.Logger(r.Context()).Error("Erreur lors du rendu du template", "template", tmpl, "error", err)
 		// Ici, rien n'a été envoyé à 'w', donc on peut envoyer une erreur propre
 		http.Error(w, "Erreur interne du serveur - Erreur de rendu", http.StatusInternalServerError)
 		return
@@ -0,0 +1,9 @@
+// Package locales embarque les catalogues de messages de l'interface (un fichier JSON par langue)
+package locales
+
+import "embed"
+
+// FS contient les catalogues (fr.json, en.json, ...)
+//
+//go:embed *.json
+var FS embed.FS
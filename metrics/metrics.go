@@ -0,0 +1,255 @@
+// Package metrics expose des compteurs et histogrammes au format texte de Prometheus
+// (version 0.0.4), sans dépendance : le serveur les publie sur /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Intervalles par défaut des histogrammes de durée, en secondes
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector est une métrique enregistrée (famille de séries partageant nom, aide et libellés)
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry regroupe les métriques publiées
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// Default est le registre des métriques du serveur
+var Default = &Registry{}
+
+// register ajoute une métrique ; un nom déjà pris est une erreur de programmation
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic("metrics: métrique déjà enregistrée : " + c.name())
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText écrit toutes les métriques au format texte de Prometheus, triées par nom
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	collectors := make([]collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler sert les métriques du registre (GET /metrics)
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		r.WriteText(w)
+	})
+}
+
+// desc décrit une famille de séries
+type desc struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func (d desc) name() string { return d.metricName }
+
+// header écrit les lignes # HELP et # TYPE
+func (d desc) header(w io.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.metricName, escapeHelp(d.help), d.metricName, kind)
+}
+
+// key identifie une série par ses valeurs de libellés
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s attend %d libellés, %d reçus", d.metricName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs formate les libellés d'une série ({route="/artists",status="200"}) ; extra est ajouté en dernier (le="...")
+func (d desc) labelPairs(key string, extra ...string) string {
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labels[i]+`="`+escapeLabel(value)+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec est un compteur par combinaison de libellés (ex. requêtes par route et statut)
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec crée et enregistre un compteur dans le registre par défaut
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{desc: desc{name, help, labels}, values: make(map[string]float64)}
+	Default.register(c)
+	return c
+}
+
+// Inc incrémente la série des valeurs de libellés données
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add ajoute v (positif) à la série des valeurs de libellés données
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	key := c.key(labelValues)
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.labelPairs(key), formatFloat(c.values[key]))
+	}
+}
+
+// HistogramVec répartit des observations (durées en secondes) en intervalles cumulés, par combinaison de libellés
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogram
+}
+
+// histogram est une série d'un HistogramVec
+type histogram struct {
+	counts []uint64 // Par intervalle (non cumulé) ; le dernier compte les valeurs au-delà (+Inf)
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec crée et enregistre un histogramme ; buckets est trié par ordre croissant (DefaultBuckets si nil)
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{desc: desc{name, help, labels}, buckets: buckets, series: make(map[string]*histogram)}
+	Default.register(h)
+	return h
+}
+
+// Observe enregistre une valeur dans la série des valeurs de libellés données
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+	i := sort.SearchFloat64s(h.buckets, v) // Premier intervalle dont la borne est >= v
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogram{counts: make([]uint64, len(h.buckets)+1)}
+		h.series[key] = s
+	}
+	s.counts[i]++
+	s.sum += v
+	s.count++
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(key, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(key, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.labelPairs(key), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.labelPairs(key), s.count)
+	}
+}
+
+// GaugeFunc est une jauge sans libellés dont la valeur est lue à chaque publication
+type GaugeFunc struct {
+	desc
+	value func() float64
+}
+
+// NewGaugeFunc crée et enregistre une jauge calculée par value
+func NewGaugeFunc(name, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{metricName: name, help: help}, value: value}
+	Default.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.metricName, formatFloat(g.value()))
+}
+
+// sortedKeys retourne les clés d'une map triées (sortie stable d'une publication à l'autre)
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatFloat formate une valeur comme Prometheus l'attend (+Inf, -Inf, NaN)
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabel échappe une valeur de libellé (\, " et saut de ligne)
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// escapeHelp échappe un texte d'aide (\ et saut de ligne)
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
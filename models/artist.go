@@ -13,11 +13,12 @@ type Artist struct {
 	ConcertDates string   `json:"concertDates"`
 	Relations    string   `json:"relations"`
 	// Champs optionnels (ex. API Spotify)
-	SpotifyURL string   `json:"-"`
-	Genres     []string `json:"-"`
-	Popularity int      `json:"-"`
-	Followers  int      `json:"-"`
-	Country    string   `json:"-"` // Pays d'origine (si disponible)
+	SpotifyID  string   `json:"spotifyId,omitempty"`
+	SpotifyURL string   `json:"spotifyUrl"`
+	Genres     []string `json:"genres"`
+	Popularity int      `json:"popularity"`
+	Followers  int      `json:"followers"`
+	Country    string   `json:"country,omitempty"` // Pays d'origine (si disponible)
 }
 
 // Location représente les lieux de concerts d'un artiste
@@ -41,15 +42,17 @@ type Relation struct {
 
 // TrackInfo représente un titre (top track Spotify)
 type TrackInfo struct {
-	Name       string `json:"name"`
-	SpotifyURL string `json:"spotifyUrl"`
-	AlbumName  string `json:"albumName"`
-	DurationMs int    `json:"durationMs"`
-	PreviewURL string `json:"previewUrl"`
+	Name       string   `json:"name"`
+	SpotifyURL string   `json:"spotifyUrl"`
+	AlbumName  string   `json:"albumName"`
+	DurationMs int      `json:"durationMs"`
+	PreviewURL string   `json:"previewUrl"`
+	Artists    []string `json:"artists"` // Artistes crédités (featurings compris)
 }
 
 // AlbumInfo représente un album Spotify
 type AlbumInfo struct {
+	ID          string `json:"id"`
 	Name        string `json:"name"`
 	SpotifyURL  string `json:"spotifyUrl"`
 	ReleaseDate string `json:"releaseDate"`
@@ -80,10 +83,23 @@ type ArtistDetail struct {
 
 // FilterOptions représente les options de filtrage
 type FilterOptions struct {
-	MinYear       int      // Année de création minimum
-	MaxYear       int      // Année de création maximum
-	MemberCount   []int    // Nombre de membres (sélection multiple)
-	Locations     []string // Lieux de concerts (sélection multiple)
-	FirstAlbumMin string   // Premier album date minimum (format: DD-MM-YYYY)
-	FirstAlbumMax string   // Premier album date maximum (format: DD-MM-YYYY)
+	MinYear       int      `json:"minYear,omitempty"`       // Année de création minimum
+	MaxYear       int      `json:"maxYear,omitempty"`       // Année de création maximum
+	MemberCount   []int    `json:"memberCount,omitempty"`   // Nombre de membres (sélection multiple)
+	Locations     []string `json:"locations,omitempty"`     // Lieux de concerts (sélection multiple)
+	FirstAlbumMin string   `json:"firstAlbumMin,omitempty"` // Premier album date minimum (format: DD-MM-YYYY)
+	FirstAlbumMax string   `json:"firstAlbumMax,omitempty"` // Premier album date maximum (format: DD-MM-YYYY)
+	Genres        []string `json:"genres,omitempty"`        // Genres (sélection multiple)
+	GenreMode     string   `json:"genreMode,omitempty"`     // GenreModeAny (au moins un genre) ou GenreModeAll (tous les genres)
+	MinPopularity int      `json:"minPopularity,omitempty"` // Popularité Spotify minimum (0-100)
+	MaxPopularity int      `json:"maxPopularity,omitempty"` // Popularité Spotify maximum (0 = pas de limite)
+	Sort          string   `json:"sort,omitempty"`          // Clé de tri ("name", "-popularity", ...), vide = ordre du catalogue
+	Page          int      `json:"page,omitempty"`          // Page demandée (1-based)
+	PerPage       int      `json:"perPage,omitempty"`       // Nombre d'artistes par page
 }
+
+// Modes de combinaison du filtre par genres
+const (
+	GenreModeAny = "or"
+	GenreModeAll = "and"
+)
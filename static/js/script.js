@@ -6,6 +6,7 @@ document.addEventListener('DOMContentLoaded', () => {
     initThemeToggle();
     initSearchSuggestions();
     initFilters();
+    initRefreshProgress();
 });
 
 // ============================================
@@ -119,6 +120,11 @@ function initSearchSuggestions() {
             suggestionsBox.style.display = 'block';
 
             const response = await fetch(`/suggestions?q=${encodeURIComponent(query)}`);
+            if (!response.ok) {
+                // Catalogue indisponible (Spotify en échec, limite d'appels...) : pas de suggestions
+                hideSuggestions();
+                return;
+            }
             const data = await response.json();
 
             suggestionsBox.innerHTML = '';
@@ -132,17 +138,29 @@ function initSearchSuggestions() {
                     div.classList.add('suggestion-item');
                     div.setAttribute('data-index', index);
                     
-                    const text = item.name || item.Name || item.text;
-                    const id = item.id || item.ID;
+                    const text = item.value || item.name || item.text;
 
-                    div.textContent = text;
+                    // Libellé typé : "Queen — artiste", "Freddie Mercury — membre"
+                    const valueSpan = document.createElement('span');
+                    valueSpan.classList.add('suggestion-value');
+                    valueSpan.textContent = text;
+                    div.appendChild(valueSpan);
+                    if (item.typeLabel) {
+                        const typeSpan = document.createElement('span');
+                        typeSpan.classList.add('suggestion-type', `suggestion-type-${item.type}`);
+                        typeSpan.textContent = ` — ${item.typeLabel}`;
+                        div.appendChild(typeSpan);
+                    }
                     
                     div.addEventListener('click', () => {
                         searchInput.value = text;
                         hideSuggestions();
                         
-                        if (id) {
-                            window.location.href = `/artist/${id}`;
+                        // Fiche artiste ou page de résultats filtrés selon le type
+                        if (item.url) {
+                            window.location.href = item.url;
+                        } else if (item.id) {
+                            window.location.href = `/artist/${item.id}`;
                         } else {
                             searchInput.closest('form').submit();
                         }
@@ -159,7 +177,11 @@ function initSearchSuggestions() {
                     suggestionsBox.appendChild(div);
                 });
             } else {
-                suggestionsBox.innerHTML = '<div class="suggestion-item" style="text-align: center;">Aucun résultat trouvé</div>';
+                const empty = document.createElement('div');
+                empty.className = 'suggestion-item';
+                empty.style.textAlign = 'center';
+                empty.textContent = suggestionsBox.dataset.empty || 'Aucun résultat trouvé';
+                suggestionsBox.replaceChildren(empty);
                 suggestionsBox.style.display = 'block';
             }
         } catch (error) {
@@ -249,3 +271,52 @@ function initFilters() {
         });
     });
 }
+
+// ============================================
+// PROGRESSION DU RAFRAÎCHISSEMENT DU CATALOGUE
+// ============================================
+
+function initRefreshProgress() {
+    const box = document.getElementById('refresh-progress');
+    if (!box || !window.EventSource) return;
+
+    const label = box.querySelector('.refresh-progress-label');
+    const bar = box.querySelector('.refresh-progress-bar');
+    // Ignorer les étapes antérieures à l'affichage de la page (rafraîchissement précédent)
+    const since = Number(box.dataset.since || 0);
+    const isNew = (e) => Number(e.lastEventId) > since;
+
+    const source = new EventSource('/events');
+
+    source.addEventListener('start', (e) => {
+        if (!isNew(e)) return;
+        bar.removeAttribute('value');
+    });
+
+    source.addEventListener('progress', (e) => {
+        if (!isNew(e)) return;
+        const data = JSON.parse(e.data);
+        bar.max = data.total;
+        bar.value = data.done;
+        // Message traduit fourni par la page : "... {done}/{total}"
+        label.textContent = box.dataset.progress
+            .replace('{done}', data.done)
+            .replace('{total}', data.total) +
+            (data.artist ? ` (${data.artist})` : '');
+    });
+
+    source.addEventListener('done', (e) => {
+        if (!isNew(e)) return;
+        source.close();
+        label.textContent = box.dataset.done;
+        window.location.reload();
+    });
+
+    source.addEventListener('failed', (e) => {
+        if (!isNew(e)) return;
+        source.close();
+        box.classList.add('refresh-failed');
+        bar.remove();
+        label.textContent = box.dataset.failed;
+    });
+}
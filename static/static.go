@@ -0,0 +1,9 @@
+// Package static embarque les fichiers statiques (CSS, JavaScript) dans le binaire.
+package static
+
+import "embed"
+
+// FS contient les fichiers statiques, chemins relatifs à static/ ("css/style.css")
+//
+//go:embed css js
+var FS embed.FS
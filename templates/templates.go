@@ -0,0 +1,10 @@
+// Package templates embarque les templates HTML dans le binaire : le serveur
+// fonctionne quel que soit le dossier de lancement.
+package templates
+
+import "embed"
+
+// FS contient les templates HTML
+//
+//go:embed *.html
+var FS embed.FS
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// assetHashLen est la longueur de l'empreinte insérée dans les URL ("style.3f2a9c1b.css")
+const assetHashLen = 8
+
+// AssetServer sert les fichiers statiques. Les URL produites par URL portent l'empreinte
+// du contenu : elles changent avec le fichier et peuvent donc être mises en cache un an.
+// En mode développement, les fichiers sont relus à chaque requête et les URL restent simples.
+type AssetServer struct {
+	fsys   fs.FS
+	prefix string // Préfixe des URL ("/static/")
+	dev    bool
+
+	hashes map[string]string // "css/style.css" -> empreinte complète (ETag)
+	byURL  map[string]string // "css/style.3f2a9c1b.css" -> "css/style.css"
+}
+
+// NewAssetServer calcule l'empreinte de chaque fichier de fsys (hors mode développement)
+func NewAssetServer(fsys fs.FS, prefix string, dev bool) (*AssetServer, error) {
+	a := &AssetServer{
+		fsys:   fsys,
+		prefix: prefix,
+		dev:    dev,
+		hashes: make(map[string]string),
+		byURL:  make(map[string]string),
+	}
+	if dev {
+		return a, nil
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) == ".go" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		hash := contentHash(data)
+		a.hashes[name] = hash
+		a.byURL[fingerprint(name, hash)] = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// contentHash retourne l'empreinte SHA-256 (hexadécimal) d'un contenu
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprint insère l'empreinte courte avant l'extension ("css/style.css" -> "css/style.3f2a9c1b.css")
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash[:assetHashLen] + ext
+}
+
+// URL retourne l'URL d'un fichier statique ("css/style.css" -> "/static/css/style.3f2a9c1b.css").
+// Un fichier inconnu garde son URL simple.
+func (a *AssetServer) URL(name string) string {
+	if hash, ok := a.hashes[name]; ok {
+		return a.prefix + fingerprint(name, hash)
+	}
+	return a.prefix + name
+}
+
+// ServeHTTP sert un fichier statique. URL avec empreinte : cache d'un an (immutable) ;
+// URL simple ou empreinte périmée : le navigateur revalide (ETag, réponse 304 si inchangé).
+func (a *AssetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		RenderError(w, r, http.StatusMethodNotAllowed, "error.method_not_allowed")
+		return
+	}
+
+	urlName := strings.TrimPrefix(r.URL.Path, a.prefix)
+	name, immutable := a.byURL[urlName]
+	if !immutable {
+		name = stripFingerprint(urlName)
+	}
+
+	data, err := fs.ReadFile(a.fsys, name)
+	if err != nil || path.Ext(name) == ".go" {
+		RenderError(w, r, http.StatusNotFound, "error.file_not_found")
+		return
+	}
+
+	hash, ok := a.hashes[name]
+	if !ok {
+		hash = contentHash(data) // Mode développement : le fichier a pu changer
+	}
+	w.Header().Set("ETag", `"`+hash[:2*assetHashLen]+`"`)
+	if immutable {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	// ServeContent déduit le type du nom et répond 304 quand If-None-Match correspond
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+}
+
+// stripFingerprint retire une empreinte (éventuellement périmée) d'un nom de fichier :
+// une page en cache peut encore demander l'ancienne version après un déploiement
+func stripFingerprint(name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	dot := strings.LastIndexByte(base, '.')
+	if dot < 0 || len(base)-dot-1 != assetHashLen {
+		return name
+	}
+	if _, err := hex.DecodeString(base[dot+1:]); err != nil {
+		return name
+	}
+	return base[:dot] + ext
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// AtomFeed est un flux Atom (RFC 4287)
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []AtomLink  `xml:"link"`
+	Author  AtomPerson  `xml:"author"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomLink est un lien de flux ou d'entrée
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// AtomPerson est un auteur
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// AtomCategory est une catégorie (genre)
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// AtomText est un texte typé (text ou html)
+type AtomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// AtomEntry est une entrée du flux
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []AtomLink     `xml:"link"`
+	Author     AtomPerson     `xml:"author"`
+	Categories []AtomCategory `xml:"category"`
+	Summary    AtomText       `xml:"summary"`
+}
+
+// NewReleasesFeed construit le flux Atom des sorties. selfURL (URL absolue du flux) sert aussi
+// d'identifiant ; siteURL pointe vers la page HTML correspondante.
+func NewReleasesFeed(title, selfURL, siteURL string, releases []Release) AtomFeed {
+	feed := AtomFeed{
+		ID:    selfURL,
+		Title: title,
+		Links: []AtomLink{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL, Rel: "alternate", Type: "text/html"},
+		},
+		Author:  AtomPerson{Name: "Groupie Tracker"},
+		Entries: []AtomEntry{},
+	}
+
+	var updated time.Time
+	for _, r := range releases {
+		if r.FirstSeen.After(updated) {
+			updated = r.FirstSeen
+		}
+
+		entry := AtomEntry{
+			// Identifiant stable : un lecteur de flux ne montre jamais deux fois la même sortie
+			ID:      fmt.Sprintf("urn:spotify:album:%s:artist:%s", r.AlbumID, r.artistKey()),
+			Title:   r.ArtistName + " — " + r.Name,
+			Updated: r.FirstSeen.UTC().Format(time.RFC3339),
+			Author:  AtomPerson{Name: r.ArtistName},
+			Summary: AtomText{Type: "html", Body: releaseSummary(r)},
+		}
+		if published, ok := ReleaseTime(r.ReleaseDate); ok {
+			entry.Published = published.UTC().Format(time.RFC3339)
+		}
+		if r.SpotifyURL != "" {
+			entry.Links = append(entry.Links, AtomLink{Href: r.SpotifyURL, Rel: "alternate", Type: "text/html"})
+		}
+		for _, genre := range r.Genres {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: genre})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if updated.IsZero() {
+		updated = time.Unix(0, 0) // Flux vide : date fixe pour des réponses stables
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+	return feed
+}
+
+// releaseSummary décrit une sortie en HTML (le HTML est ensuite échappé dans le XML)
+func releaseSummary(r Release) string {
+	var sb strings.Builder
+	if r.ImageURL != "" {
+		fmt.Fprintf(&sb, `<p><img src="%s" alt="" width="300"></p>`, html.EscapeString(r.ImageURL))
+	}
+	fmt.Fprintf(&sb, "<p>Nouvelle sortie de <strong>%s</strong> : %s", html.EscapeString(r.ArtistName), html.EscapeString(r.Name))
+	if r.ReleaseDate != "" {
+		fmt.Fprintf(&sb, " (%s)", html.EscapeString(r.ReleaseDate))
+	}
+	if r.TotalTracks > 0 {
+		fmt.Fprintf(&sb, ", %d titre(s)", r.TotalTracks)
+	}
+	sb.WriteString("</p>")
+	return sb.String()
+}
+
+// WriteAtom encode un flux Atom
+func WriteAtom(w io.Writer, feed AtomFeed) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
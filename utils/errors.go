@@ -1,9 +1,8 @@
 package utils
 
 import (
-	"html/template"
-	"log"
 	"net/http"
+	"strconv"
 )
 
 // ErrorData représente les données pour une page d'erreur
@@ -13,45 +12,47 @@ type ErrorData struct {
 	Title      string
 }
 
-// RenderError affiche une page d'erreur personnalisée
-func RenderError(w http.ResponseWriter, statusCode int, message string) {
+// RenderError affiche une page d'erreur personnalisée dans la langue de la requête.
+// key est la clé du message dans le catalogue ("error.artist_not_found") ; args complète ses verbes fmt.
+func RenderError(w http.ResponseWriter, r *http.Request, statusCode int, key string, args ...interface{}) {
+	lang := Language(r)
+	message := Messages.T(lang, key, args...)
 	w.WriteHeader(statusCode)
 
 	data := ErrorData{
 		StatusCode: statusCode,
 		Message:    message,
-		Title:      getErrorTitle(statusCode),
+		Title:      getErrorTitle(lang, statusCode),
 	}
 
-	tmpl, err := template.ParseFiles("templates/error.html")
+	if Templates == nil {
+		http.Error(w, message, statusCode)
+		return
+	}
+	tmpl, err := Templates.Lookup(lang, "error.html")
 	if err != nil {
-		log.Printf("Erreur lors du parsing du template d'erreur: %v", err)
+		Logger(r.Context()).Error("Erreur lors du chargement du template d'erreur", "error", err)
 		http.Error(w, message, statusCode)
 		return
 	}
 
 	if err := tmpl.Execute(w, data); err != nil {
-		log.Printf("Erreur lors de l'exécution du template d'erreur: %v", err)
+		Logger(r.Context()).Error("Erreur lors de l'exécution du template d'erreur", "error", err)
 		http.Error(w, message, statusCode)
 	}
 }
 
-// getErrorTitle retourne le titre approprié selon le code d'erreur
-func getErrorTitle(statusCode int) string {
-	switch statusCode {
-	case http.StatusNotFound:
-		return "Page non trouvée"
-	case http.StatusBadRequest:
-		return "Requête invalide"
-	case http.StatusInternalServerError:
-		return "Erreur serveur"
-	default:
-		return "Erreur"
+// getErrorTitle retourne le titre approprié selon le code d'erreur ("error.title.404"),
+// ou le titre générique
+func getErrorTitle(lang string, statusCode int) string {
+	if title, ok := Messages.Lookup(lang, "error.title."+strconv.Itoa(statusCode)); ok {
+		return title
 	}
+	return Messages.T(lang, "error.title")
 }
 
 // HandleError gère les erreurs de manière centralisée
-func HandleError(w http.ResponseWriter, err error, statusCode int) {
-	log.Printf("Erreur: %v", err)
-	RenderError(w, statusCode, err.Error())
+func HandleError(w http.ResponseWriter, r *http.Request, err error, statusCode int) {
+	Logger(r.Context()).Error("Erreur", "error", err)
+	RenderError(w, r, statusCode, err.Error())
 }
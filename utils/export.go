@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"groupie-tracker-ng/models"
+)
+
+// Formats d'export des listes d'artistes
+const (
+	ExportCSV    = "csv"
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+)
+
+// ExportFormats associe chaque format d'export à son type MIME
+var ExportFormats = map[string]string{
+	ExportCSV:    "text/csv; charset=utf-8",
+	ExportJSON:   "application/json; charset=utf-8",
+	ExportNDJSON: "application/x-ndjson; charset=utf-8",
+}
+
+// exportFlushEvery est le nombre de lignes écrites entre deux envois au client
+const exportFlushEvery = 50
+
+// ExportRow est une ligne d'export d'artiste
+type ExportRow struct {
+	Name           string   `json:"name"`
+	Genres         []string `json:"genres"`
+	Popularity     int      `json:"popularity"`
+	Followers      int      `json:"followers"`
+	FirstAlbum     string   `json:"firstAlbum"`
+	FirstAlbumDate string   `json:"firstAlbumDate"`
+	CreationYear   *int     `json:"creationYear"` // null si inconnue
+	SpotifyURL     string   `json:"spotifyUrl"`
+}
+
+// exportColumns est l'en-tête CSV, dans l'ordre des champs de ExportRow
+var exportColumns = []string{"name", "genres", "popularity", "followers", "firstAlbum", "firstAlbumDate", "creationYear", "spotifyUrl"}
+
+// NewExportRow extrait d'un artiste les colonnes exportées
+func NewExportRow(artist models.Artist) ExportRow {
+	genres := artist.Genres
+	if genres == nil {
+		genres = []string{}
+	}
+	row := ExportRow{
+		Name:           artist.Name,
+		Genres:         genres,
+		Popularity:     artist.Popularity,
+		Followers:      artist.Followers,
+		FirstAlbum:     artist.FirstAlbum,
+		FirstAlbumDate: artist.FirstAlbumDate,
+		SpotifyURL:     artist.SpotifyURL,
+	}
+	if artist.CreationDate > 0 {
+		year := artist.CreationDate
+		row.CreationYear = &year
+	}
+	return row
+}
+
+// ExportArtists écrit les artistes dans le format demandé, ligne par ligne : la sortie
+// est envoyée au client au fil de l'eau (si w est un http.Flusher) sans être construite en mémoire
+func ExportArtists(w io.Writer, format string, artists []models.Artist) error {
+	flusher, _ := w.(http.Flusher)
+	flush := func(i int) {
+		if flusher != nil && (i+1)%exportFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+
+	switch format {
+	case ExportCSV:
+		// BOM : les tableurs reconnaissent l'UTF-8 (accents des noms)
+		if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+			return err
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportColumns); err != nil {
+			return err
+		}
+		for i, artist := range artists {
+			if err := cw.Write(NewExportRow(artist).csvRecord()); err != nil {
+				return err
+			}
+			if (i+1)%exportFlushEvery == 0 {
+				cw.Flush()
+				flush(i)
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case ExportJSON:
+		if _, err := io.WriteString(w, "[\n"); err != nil {
+			return err
+		}
+		for i, artist := range artists {
+			row, err := json.Marshal(NewExportRow(artist))
+			if err != nil {
+				return err
+			}
+			sep := ",\n"
+			if i == len(artists)-1 {
+				sep = "\n"
+			}
+			if _, err := fmt.Fprintf(w, "  %s%s", row, sep); err != nil {
+				return err
+			}
+			flush(i)
+		}
+		_, err := io.WriteString(w, "]\n")
+		return err
+
+	case ExportNDJSON:
+		enc := json.NewEncoder(w)
+		for i, artist := range artists {
+			if err := enc.Encode(NewExportRow(artist)); err != nil {
+				return err
+			}
+			flush(i)
+		}
+		return nil
+	}
+	return fmt.Errorf("format d'export inconnu : %q", format)
+}
+
+// csvRecord convertit la ligne en enregistrement CSV (genres séparés par "; ")
+func (r ExportRow) csvRecord() []string {
+	creationYear := ""
+	if r.CreationYear != nil {
+		creationYear = strconv.Itoa(*r.CreationYear)
+	}
+	return []string{
+		csvSafe(r.Name),
+		csvSafe(strings.Join(r.Genres, "; ")),
+		strconv.Itoa(r.Popularity),
+		strconv.Itoa(r.Followers),
+		csvSafe(r.FirstAlbum),
+		r.FirstAlbumDate,
+		creationYear,
+		r.SpotifyURL,
+	}
+}
+
+// csvSafe neutralise les cellules qu'un tableur interpréterait comme une formule
+// ("=", "+", "@"). Le "-" initial est conservé : des noms d'artistes commencent ainsi (-M-).
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"groupie-tracker-ng/models"
+)
+
+// FacetCount représente une option de filtre avec le nombre d'artistes correspondants
+type FacetCount struct {
+	Value    string // Valeur envoyée dans la requête
+	Label    string // Libellé affiché
+	Count    int    // Nombre d'artistes correspondants compte tenu des autres filtres actifs
+	Selected bool   // Option actuellement sélectionnée
+}
+
+// Facets regroupe les compteurs de chaque filtre à options
+type Facets struct {
+	Genres    []FacetCount
+	Members   []FacetCount
+	Locations []FacetCount
+}
+
+// memberFacetLabels associe chaque valeur du filtre "membres" à son libellé
+var memberFacetLabels = []struct {
+	Value int
+	Label string
+}{
+	{1, "1 (Solo)"},
+	{2, "2"},
+	{3, "3"},
+	{4, "4"},
+	{5, "5+"},
+}
+
+// ComputeFacets calcule, pour chaque option de filtre, le nombre d'artistes qui
+// correspondraient en la cochant, compte tenu des autres filtres actifs.
+func ComputeFacets(artists []models.Artist, options models.FilterOptions, locations *LocationIndex) Facets {
+	return Facets{
+		Genres:    genreFacets(artists, options, locations),
+		Members:   memberFacets(artists, options, locations),
+		Locations: locationFacets(artists, options, locations),
+	}
+}
+
+// genreFacets compte les artistes par genre.
+// En mode OR, la sélection de genres est ignorée ; en mode AND, elle est conservée
+// et chaque compteur indique le résultat si le genre était ajouté.
+func genreFacets(artists []models.Artist, options models.FilterOptions, locations *LocationIndex) []FacetCount {
+	others := options
+	if options.GenreMode != models.GenreModeAll {
+		others.Genres = nil
+	}
+	base := FilterArtists(artists, others, locations)
+
+	selected := make(map[string]bool, len(options.Genres))
+	for _, genre := range options.Genres {
+		selected[strings.ToLower(genre)] = true
+	}
+
+	counts := make(map[string]int)
+	for _, artist := range base {
+		for _, genre := range artist.Genres {
+			counts[strings.ToLower(genre)]++
+		}
+	}
+
+	// Toujours afficher les genres sélectionnés, même sans résultat
+	for genre := range selected {
+		if _, ok := counts[genre]; !ok {
+			counts[genre] = 0
+		}
+	}
+
+	facets := make([]FacetCount, 0, len(counts))
+	for genre, count := range counts {
+		facets = append(facets, FacetCount{
+			Value:    genre,
+			Label:    genre,
+			Count:    count,
+			Selected: selected[genre],
+		})
+	}
+	sortFacets(facets)
+	return facets
+}
+
+// memberFacets compte les artistes par nombre de membres (sélection de membres ignorée)
+func memberFacets(artists []models.Artist, options models.FilterOptions, locations *LocationIndex) []FacetCount {
+	others := options
+	others.MemberCount = nil
+	base := FilterArtists(artists, others, locations)
+
+	selected := make(map[int]bool, len(options.MemberCount))
+	for _, mc := range options.MemberCount {
+		selected[mc] = true
+	}
+
+	facets := make([]FacetCount, 0, len(memberFacetLabels))
+	for _, m := range memberFacetLabels {
+		count := 0
+		for _, artist := range base {
+			if matchesMemberCount(estimateMemberCount(artist), []int{m.Value}) {
+				count++
+			}
+		}
+		facets = append(facets, FacetCount{
+			Value:    strconv.Itoa(m.Value),
+			Label:    m.Label,
+			Count:    count,
+			Selected: selected[m.Value],
+		})
+	}
+	return facets
+}
+
+// locationFacets compte les artistes par lieu de concert (sélection de lieux ignorée)
+func locationFacets(artists []models.Artist, options models.FilterOptions, locations *LocationIndex) []FacetCount {
+	others := options
+	others.Locations = nil
+	base := FilterArtists(artists, others, locations)
+
+	selected := make(map[string]bool, len(options.Locations))
+	for _, loc := range options.Locations {
+		selected[NormalizeLocation(loc)] = true
+	}
+
+	labels := GetPopularLocations(locations)
+	facets := make([]FacetCount, 0, len(labels))
+	for _, label := range labels {
+		ids := locations.ArtistIDs([]string{label})
+		count := 0
+		for _, artist := range base {
+			if ids[artist.ID] {
+				count++
+			}
+		}
+		facets = append(facets, FacetCount{
+			Value:    label,
+			Label:    label,
+			Count:    count,
+			Selected: selected[NormalizeLocation(label)],
+		})
+	}
+	sortFacets(facets)
+	return facets
+}
+
+// sortFacets trie les options par nombre d'artistes décroissant puis par libellé
+func sortFacets(facets []FacetCount) {
+	sort.SliceStable(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Label < facets[j].Label
+	})
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +11,41 @@ import (
 	"groupie-tracker-ng/models"
 )
 
+// FilterParam décrit un paramètre de requête compris par ParseFilterOptions (documentation de l'API)
+type FilterParam struct {
+	Name        string
+	Type        string // "integer" ou "string"
+	Multi       bool   // Paramètre répétable (?genre=rock&genre=pop)
+	Enum        []string
+	Description string
+}
+
+// FilterParams liste les paramètres lus par ParseFilterOptions
+var FilterParams = []FilterParam{
+	{Name: "minYear", Type: "integer", Description: "Année de création minimum"},
+	{Name: "maxYear", Type: "integer", Description: "Année de création maximum"},
+	{Name: "memberCount", Type: "integer", Multi: true, Description: "Nombre de membres (5 = 5 et plus)"},
+	{Name: "location", Type: "string", Multi: true, Description: "Lieu de concert (\"Paris\", \"paris-france\")"},
+	{Name: "genre", Type: "string", Multi: true, Description: "Genre musical"},
+	{Name: "genreMode", Type: "string", Enum: []string{models.GenreModeAny, models.GenreModeAll}, Description: "Combinaison des genres : au moins un (or) ou tous (and)"},
+	{Name: "firstAlbumMin", Type: "string", Description: "Date minimum du premier album (YYYY, YYYY-MM, YYYY-MM-DD ou DD-MM-YYYY)"},
+	{Name: "firstAlbumMax", Type: "string", Description: "Date maximum du premier album (YYYY, YYYY-MM, YYYY-MM-DD ou DD-MM-YYYY)"},
+	{Name: "minPopularity", Type: "integer", Description: "Popularité Spotify minimum (0-100)"},
+	{Name: "maxPopularity", Type: "integer", Description: "Popularité Spotify maximum (1-100)"},
+	{Name: "sort", Type: "string", Enum: sortValues(), Description: "Tri (préfixe \"-\" = décroissant)"},
+	{Name: "page", Type: "integer", Description: "Page demandée (à partir de 1)"},
+	{Name: "perPage", Type: "integer", Description: "Artistes par page (24 par défaut, 100 maximum)"},
+}
+
+// sortValues retourne les valeurs acceptées par le paramètre "sort"
+func sortValues() []string {
+	values := make([]string, len(SortOptions))
+	for i, option := range SortOptions {
+		values[i] = option.Value
+	}
+	return values
+}
+
 // ParseFilterOptions parse les paramètres de requête en FilterOptions
 func ParseFilterOptions(queryParams url.Values) models.FilterOptions {
 	options := models.FilterOptions{}
@@ -43,6 +79,20 @@ func ParseFilterOptions(queryParams url.Values) models.FilterOptions {
 		options.Locations = locations
 	}
 
+	// Genres (sélection multiple, mode OR par défaut)
+	if genres := queryParams["genre"]; len(genres) > 0 {
+		options.Genres = make([]string, 0, len(genres))
+		for _, genre := range genres {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				options.Genres = append(options.Genres, genre)
+			}
+		}
+	}
+	options.GenreMode = models.GenreModeAny
+	if strings.EqualFold(queryParams.Get("genreMode"), models.GenreModeAll) {
+		options.GenreMode = models.GenreModeAll
+	}
+
 	// Premier album date minimum
 	if firstAlbumMin := queryParams.Get("firstAlbumMin"); firstAlbumMin != "" {
 		options.FirstAlbumMin = firstAlbumMin
@@ -53,13 +103,42 @@ func ParseFilterOptions(queryParams url.Values) models.FilterOptions {
 		options.FirstAlbumMax = firstAlbumMax
 	}
 
+	// Popularité Spotify (0-100)
+	if minPop, err := strconv.Atoi(queryParams.Get("minPopularity")); err == nil && minPop > 0 {
+		options.MinPopularity = minPop
+	}
+	if maxPop, err := strconv.Atoi(queryParams.Get("maxPopularity")); err == nil && maxPop > 0 {
+		options.MaxPopularity = maxPop
+	}
+
+	// Tri (ignoré si inconnu)
+	if sortKey := queryParams.Get("sort"); IsValidSort(sortKey) {
+		options.Sort = sortKey
+	}
+
+	// Pagination
+	if page, err := strconv.Atoi(queryParams.Get("page")); err == nil && page > 0 {
+		options.Page = page
+	}
+	if perPage, err := strconv.Atoi(queryParams.Get("perPage")); err == nil && perPage > 0 {
+		options.PerPage = perPage
+	}
+
 	return options
 }
 
-// FilterArtists filtre les artistes selon les critères
-func FilterArtists(artists []models.Artist, options models.FilterOptions) []models.Artist {
+// FilterArtists filtre les artistes selon les critères.
+// Le filtre par lieu s'appuie sur l'index des concerts ; sans aucun lieu connu (index nil ou vide),
+// il est ignoré plutôt que d'écarter tous les artistes.
+func FilterArtists(artists []models.Artist, options models.FilterOptions, locations *LocationIndex) []models.Artist {
 	filtered := make([]models.Artist, 0)
 
+	var locationIDs map[int]bool
+	filterLocations := len(options.Locations) > 0 && locations.Len() > 0
+	if filterLocations {
+		locationIDs = locations.ArtistIDs(options.Locations)
+	}
+
 	for _, artist := range artists {
 		// Filtrer par année de création (ignorer si non fournie, ex. API Spotify)
 		if options.MinYear > 0 && artist.CreationDate != 0 && artist.CreationDate < options.MinYear {
@@ -69,57 +148,22 @@ func FilterArtists(artists []models.Artist, options models.FilterOptions) []mode
 			continue
 		}
 
+		// Filtrer par popularité
+		if options.MinPopularity > 0 && artist.Popularity < options.MinPopularity {
+			continue
+		}
+		if options.MaxPopularity > 0 && artist.Popularity > options.MaxPopularity {
+			continue
+		}
+
 		// Filtrer par nombre de membres
-		if len(options.MemberCount) > 0 {
-			memberCount := len(artist.Members)
-			
-			// Si pas de membres dans les données, estimer basé sur le nom
-			if memberCount == 0 {
-				nameLower := strings.ToLower(artist.Name)
-				// Détecter les groupes (mots-clés communs)
-				groupKeywords := []string{" & ", " and ", " feat", " ft.", " feat.", " featuring", " vs ", " x ", " + "}
-				isGroup := false
-				for _, keyword := range groupKeywords {
-					if strings.Contains(nameLower, keyword) {
-						isGroup = true
-						break
-					}
-				}
-				
-				// Détecter les groupes avec "The" au début (souvent des groupes)
-				if strings.HasPrefix(nameLower, "the ") && len(nameLower) > 4 {
-					isGroup = true
-				}
-				
-				// Détecter les groupes avec des mots comme "band", "group", "collective", etc.
-				groupWords := []string{" band", " group", " collective", " ensemble", " orchestra", " quartet", " trio"}
-				for _, word := range groupWords {
-					if strings.Contains(nameLower, word) {
-						isGroup = true
-						break
-					}
-				}
-				
-				if isGroup {
-					memberCount = 2 // Groupe (au moins 2 membres)
-				} else {
-					memberCount = 1 // Probablement solo
-				}
-			}
-			
-			found := false
-			for _, mc := range options.MemberCount {
-				if mc == 5 && memberCount >= 5 {
-					found = true
-					break
-				} else if memberCount == mc {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		if len(options.MemberCount) > 0 && !matchesMemberCount(estimateMemberCount(artist), options.MemberCount) {
+			continue
+		}
+
+		// Filtrer par genres (au moins un genre en mode OR, tous en mode AND)
+		if len(options.Genres) > 0 && !matchesGenres(artist, options.Genres, options.GenreMode) {
+			continue
 		}
 
 		// Filtrer par premier album (utiliser FirstAlbumDate si disponible, sinon essayer de parser FirstAlbum)
@@ -147,77 +191,16 @@ func FilterArtists(artists []models.Artist, options models.FilterOptions) []mode
 			}
 
 			if options.FirstAlbumMax != "" {
-				maxDate, err := parseDate(options.FirstAlbumMax)
+				maxDate, err := dateBound(options.FirstAlbumMax, true)
 				if err == nil && albumDate.After(maxDate) {
 					continue
 				}
 			}
 		}
 
-		// Filtrer par lieu (villes/pays populaires)
-		// Note: Comme Spotify ne fournit pas directement les lieux, on utilise une correspondance approximative
-		// basée sur le nom de l'artiste et les genres pour déterminer une origine probable
-		if len(options.Locations) > 0 {
-			hasLocation := false
-			nameLower := strings.ToLower(artist.Name)
-			
-			// Correspondances approximatives basées sur le nom et les genres
-			locationKeywords := map[string][]string{
-				"paris":     {"french", "france", "français", "paris"},
-				"lyon":      {"french", "france", "français"},
-				"marseille": {"french", "france", "français"},
-				"london":    {"british", "uk", "england", "english"},
-				"manchester": {"british", "uk", "england"},
-				"new york":   {"american", "usa", "us", "hip-hop", "rap"},
-				"los angeles": {"american", "usa", "us", "california"},
-				"berlin":     {"german", "germany", "deutschland", "electronic"},
-				"madrid":     {"spanish", "spain", "español"},
-				"barcelona":  {"spanish", "spain", "catalan"},
-				"rome":       {"italian", "italy", "italia"},
-				"milan":      {"italian", "italy"},
-				"amsterdam":  {"dutch", "netherlands", "holland"},
-				"tokyo":      {"japanese", "japan", "j-pop"},
-				"seoul":      {"korean", "korea", "k-pop"},
-			}
-			
-			for _, selectedLoc := range options.Locations {
-				locLower := strings.ToLower(selectedLoc)
-				
-				// Vérifier si le nom de l'artiste contient le lieu
-				if strings.Contains(nameLower, locLower) {
-					hasLocation = true
-					break
-				}
-				
-				// Vérifier les mots-clés associés au lieu
-				if keywords, ok := locationKeywords[locLower]; ok {
-					for _, keyword := range keywords {
-						// Vérifier dans le nom
-						if strings.Contains(nameLower, keyword) {
-							hasLocation = true
-							break
-						}
-						// Vérifier dans les genres
-						for _, genre := range artist.Genres {
-							if strings.Contains(strings.ToLower(genre), keyword) {
-								hasLocation = true
-								break
-							}
-						}
-						if hasLocation {
-							break
-						}
-					}
-				}
-				
-				if hasLocation {
-					break
-				}
-			}
-			
-			if !hasLocation {
-				continue
-			}
+		// Filtrer par lieu de concert (index construit depuis les relations)
+		if filterLocations && !locationIDs[artist.ID] {
+			continue
 		}
 
 		filtered = append(filtered, artist)
@@ -226,7 +209,69 @@ func FilterArtists(artists []models.Artist, options models.FilterOptions) []mode
 	return filtered
 }
 
-// parseDate parse une date au format DD-MM-YYYY ou YYYY-MM-DD
+// estimateMemberCount retourne le nombre de membres, estimé depuis le nom si les données manquent
+func estimateMemberCount(artist models.Artist) int {
+	if len(artist.Members) > 0 {
+		return len(artist.Members)
+	}
+
+	nameLower := strings.ToLower(artist.Name)
+	// Détecter les groupes (mots-clés communs)
+	groupKeywords := []string{" & ", " and ", " feat", " ft.", " feat.", " featuring", " vs ", " x ", " + "}
+	for _, keyword := range groupKeywords {
+		if strings.Contains(nameLower, keyword) {
+			return 2
+		}
+	}
+
+	// Détecter les groupes avec "The" au début (souvent des groupes)
+	if strings.HasPrefix(nameLower, "the ") && len(nameLower) > 4 {
+		return 2
+	}
+
+	// Détecter les groupes avec des mots comme "band", "group", "collective", etc.
+	groupWords := []string{" band", " group", " collective", " ensemble", " orchestra", " quartet", " trio"}
+	for _, word := range groupWords {
+		if strings.Contains(nameLower, word) {
+			return 2 // Groupe (au moins 2 membres)
+		}
+	}
+
+	return 1 // Probablement solo
+}
+
+// matchesMemberCount vérifie si un nombre de membres correspond à la sélection (5 = 5 et plus)
+func matchesMemberCount(memberCount int, selected []int) bool {
+	for _, mc := range selected {
+		if mc == 5 && memberCount >= 5 {
+			return true
+		} else if memberCount == mc {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesGenres vérifie les genres d'un artiste selon le mode (GenreModeAll = tous requis)
+func matchesGenres(artist models.Artist, genres []string, mode string) bool {
+	artistGenres := make(map[string]bool, len(artist.Genres))
+	for _, genre := range artist.Genres {
+		artistGenres[strings.ToLower(genre)] = true
+	}
+
+	for _, genre := range genres {
+		has := artistGenres[strings.ToLower(genre)]
+		if mode == models.GenreModeAll && !has {
+			return false
+		}
+		if mode != models.GenreModeAll && has {
+			return true
+		}
+	}
+	return mode == models.GenreModeAll
+}
+
+// parseDate parse une date au format DD-MM-YYYY, YYYY-MM-DD, YYYY-MM ou YYYY
 func parseDate(dateStr string) (time.Time, error) {
 	// Essayer d'abord le format DD-MM-YYYY
 	if t, err := time.Parse("02-01-2006", dateStr); err == nil {
@@ -236,6 +281,10 @@ func parseDate(dateStr string) (time.Time, error) {
 	if t, err := time.Parse("2006-01-02", dateStr); err == nil {
 		return t, nil
 	}
+	// Essayer le format YYYY-MM
+	if t, err := time.Parse("2006-01", dateStr); err == nil {
+		return t, nil
+	}
 	// Essayer juste l'année YYYY
 	if t, err := time.Parse("2006", dateStr); err == nil {
 		return t, nil
@@ -243,6 +292,25 @@ func parseDate(dateStr string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("format de date non reconnu: %s", dateStr)
 }
 
+// dayLayout est le format des bornes de date calculées (YYYY-MM-DD)
+const dayLayout = "2006-01-02"
+
+// dateBound lit une borne de date (formats de parseDate). Une borne haute partielle (YYYY ou
+// YYYY-MM) va jusqu'au dernier jour de l'année ou du mois : "<=2010" garde tout 2010.
+func dateBound(value string, upper bool) (time.Time, error) {
+	t, err := parseDate(value)
+	if err != nil || !upper {
+		return t, err
+	}
+	switch len(value) {
+	case len("2006"):
+		return t.AddDate(1, 0, -1), nil
+	case len("2006-01"):
+		return t.AddDate(0, 1, -1), nil
+	}
+	return t, nil
+}
+
 // parseSpotifyDate parse une date Spotify (YYYY, YYYY-MM, ou YYYY-MM-DD)
 func parseSpotifyDate(dateStr string) (time.Time, error) {
 	if len(dateStr) == 4 {
@@ -264,39 +332,11 @@ func FilterArtistsByLocation(artists []models.Artist, relations []models.Relatio
 		return artists
 	}
 
-	// Créer un map pour vérifier rapidement si un lieu est dans la liste
-	locationMap := make(map[string]bool)
-	for _, loc := range locations {
-		locationMap[strings.ToLower(loc)] = true
-	}
+	ids := BuildLocationIndex(relations).ArtistIDs(locations)
 
 	filtered := make([]models.Artist, 0)
-
 	for _, artist := range artists {
-		// Trouver les relations de cet artiste
-		var artistRelation *models.Relation
-		for i := range relations {
-			if relations[i].ID == artist.ID {
-				artistRelation = &relations[i]
-				break
-			}
-		}
-
-		if artistRelation == nil {
-			continue
-		}
-
-		// Vérifier si l'artiste a des concerts dans les lieux demandés
-		hasLocation := false
-		for location := range artistRelation.DatesLocations {
-			locationLower := strings.ToLower(location)
-			if locationMap[locationLower] {
-				hasLocation = true
-				break
-			}
-		}
-
-		if hasLocation {
+		if ids[artist.ID] {
 			filtered = append(filtered, artist)
 		}
 	}
@@ -310,26 +350,10 @@ func FilterArtistsByGenres(artists []models.Artist, genres []string) []models.Ar
 		return artists
 	}
 
-	// Créer un map pour vérifier rapidement si un genre est dans la liste
-	genreMap := make(map[string]bool)
-	for _, genre := range genres {
-		genreMap[strings.ToLower(genre)] = true
-	}
-
 	filtered := make([]models.Artist, 0)
-
 	for _, artist := range artists {
 		// Vérifier si l'artiste a au moins un des genres demandés
-		hasGenre := false
-		for _, genre := range artist.Genres {
-			genreLower := strings.ToLower(genre)
-			if genreMap[genreLower] {
-				hasGenre = true
-				break
-			}
-		}
-
-		if hasGenre {
+		if matchesGenres(artist, genres, models.GenreModeAny) {
 			filtered = append(filtered, artist)
 		}
 	}
@@ -350,28 +374,11 @@ func GetUniqueGenres(artists []models.Artist) []string {
 	for genre := range genreMap {
 		genres = append(genres, genre)
 	}
+	sort.Strings(genres)
 	return genres
 }
 
-// GetPopularLocations retourne une liste de lieux populaires pour le filtre
-func GetPopularLocations() []string {
-	return []string{
-		"Paris", "Lyon", "Marseille", "Toulouse", "Nice", "Nantes", "Strasbourg", "Montpellier",
-		"Bordeaux", "Lille", "Rennes", "Reims", "Le Havre", "Saint-Étienne", "Toulon",
-		"London", "Manchester", "Liverpool", "Birmingham", "Glasgow", "Edinburgh",
-		"New York", "Los Angeles", "Chicago", "Houston", "Phoenix", "Philadelphia",
-		"Berlin", "Munich", "Hamburg", "Cologne", "Frankfurt", "Stuttgart",
-		"Madrid", "Barcelona", "Valencia", "Seville", "Bilbao",
-		"Rome", "Milan", "Naples", "Turin", "Palermo",
-		"Amsterdam", "Rotterdam", "Utrecht", "The Hague",
-		"Brussels", "Antwerp", "Ghent",
-		"Vienna", "Zurich", "Geneva", "Basel",
-		"Stockholm", "Oslo", "Copenhagen", "Helsinki",
-		"Warsaw", "Prague", "Budapest", "Bucharest",
-		"Moscow", "Saint Petersburg",
-		"Tokyo", "Seoul", "Beijing", "Shanghai", "Hong Kong",
-		"Sydney", "Melbourne", "Auckland",
-		"Toronto", "Montreal", "Vancouver",
-		"Mexico City", "São Paulo", "Rio de Janeiro", "Buenos Aires",
-	}
+// GetPopularLocations retourne les lieux de concerts connus, du plus fréquenté au moins fréquenté
+func GetPopularLocations(locations *LocationIndex) []string {
+	return locations.Popular()
 }
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// diacritics associe les lettres accentuées (latin) à leur forme sans accent
+var diacritics = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ș': "s",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'ț': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'æ': "ae", 'œ': "oe", 'ß': "ss", 'þ': "th",
+}
+
+// FoldText normalise un texte pour la recherche : minuscules, accents retirés,
+// ponctuation remplacée par des espaces et espaces multiples réduits.
+// "Beyoncé" -> "beyonce", "AC/DC" -> "ac dc", "Sigur Rós" -> "sigur ros".
+func FoldText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	space := true // évite les espaces en tête et en double
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := diacritics[r]; ok {
+			b.WriteString(folded)
+			space = false
+			continue
+		}
+		// Marques combinantes (forme décomposée NFD) : ignorées
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		// Apostrophes et points ne séparent pas les mots ("Guns N' Roses", "Dr. Dre")
+		if r == '\'' || r == '’' || r == '.' {
+			continue
+		}
+		if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
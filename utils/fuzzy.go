@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"strings"
+)
+
+// MatchKind qualifie la façon dont une requête correspond à un texte, de la plus faible à la plus forte
+type MatchKind int
+
+const (
+	MatchNone       MatchKind = iota
+	MatchFuzzy                // Correspondance approchée (fautes de frappe)
+	MatchSubstring            // Sous-chaîne au milieu d'un mot
+	MatchWordPrefix           // Début d'un mot autre que le premier
+	MatchPrefix               // Début du texte
+	MatchExact                // Texte identique
+)
+
+// Match décrit le résultat d'une comparaison requête / texte (tous deux repliés par FoldText)
+type Match struct {
+	Kind     MatchKind
+	Distance int // Nombre de fautes tolérées (MatchFuzzy uniquement)
+	Words    int // Nombre de mots de la requête (MatchFuzzy uniquement)
+}
+
+// Typos retourne le nombre moyen de fautes par mot de la requête (maxTypos au plus) :
+// une requête de plusieurs mots, chacun assez proche, n'est pas pénalisée pour sa longueur
+func (m Match) Typos() float64 {
+	if m.Words == 0 {
+		return float64(m.Distance)
+	}
+	return float64(m.Distance) / float64(m.Words)
+}
+
+// Quality retourne une note entre 0 et 1 selon le type de correspondance
+func (m Match) Quality() float64 {
+	switch m.Kind {
+	case MatchExact:
+		return 1
+	case MatchPrefix:
+		return 0.9
+	case MatchWordPrefix:
+		return 0.8
+	case MatchSubstring:
+		return 0.6
+	case MatchFuzzy:
+		return 0.5 - 0.1*m.Typos() // 0,3 au moins : toujours une correspondance
+	default:
+		return 0
+	}
+}
+
+// MatchText compare une requête à un texte, tous deux déjà repliés par FoldText
+func MatchText(text, query string) Match {
+	if query == "" || text == "" {
+		return Match{}
+	}
+	if text == query {
+		return Match{Kind: MatchExact}
+	}
+	if strings.HasPrefix(text, query) {
+		return Match{Kind: MatchPrefix}
+	}
+	if strings.Contains(" "+text, " "+query) {
+		return Match{Kind: MatchWordPrefix}
+	}
+	if strings.Contains(text, query) {
+		return Match{Kind: MatchSubstring}
+	}
+	if distance, ok := fuzzyDistance(text, query); ok {
+		return Match{Kind: MatchFuzzy, Distance: distance, Words: len(strings.Fields(query))}
+	}
+	return Match{}
+}
+
+// fuzzyDistance vérifie que chaque mot de la requête correspond à un mot du texte
+// à quelques fautes près, et retourne le total des fautes
+func fuzzyDistance(text, query string) (int, bool) {
+	textWords := strings.Fields(text)
+	total := 0
+
+	for _, qw := range strings.Fields(query) {
+		tolerance := typoTolerance(qw)
+		if tolerance == 0 {
+			// Mot trop court pour tolérer une faute : il doit apparaître tel quel
+			if !strings.Contains(text, qw) {
+				return 0, false
+			}
+			continue
+		}
+
+		best := tolerance + 1
+		for _, tw := range textWords {
+			d := editDistance(tw, qw)
+			// Saisie en cours : comparer aussi au début du mot ("eminen" ~ "eminem")
+			if r := []rune(tw); len(r) > len([]rune(qw)) {
+				if dp := editDistance(string(r[:len([]rune(qw))]), qw); dp < d {
+					d = dp
+				}
+			}
+			if d < best {
+				best = d
+			}
+		}
+		if best > tolerance {
+			return 0, false
+		}
+		total += best
+	}
+
+	return total, true
+}
+
+// maxTypos est le nombre maximum de fautes tolérées dans un mot (voir typoTolerance)
+const maxTypos = 2
+
+// typoTolerance retourne le nombre de fautes acceptées selon la longueur du mot
+func typoTolerance(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return maxTypos
+	}
+}
+
+// editDistance calcule la distance de Damerau-Levenshtein (transpositions adjacentes comprises)
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// Trois lignes suffisent : la précédente, l'avant-dernière (transpositions) et la courante
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
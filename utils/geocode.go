@@ -95,10 +95,16 @@ var cityCoords = map[string]Coords{
 
 // GetCoords retourne les coordonnées pour un lieu (ex: "london-uk"). Insensible à la casse.
 func GetCoords(location string) (lat, lng float64, ok bool) {
-	key := strings.ToLower(strings.ReplaceAll(location, " ", "_"))
-	if c, ok := cityCoords[key]; ok {
+	if c, known := LookupCoords(location); known {
 		return c.Lat, c.Lng, true
 	}
 	// Inconnu : position par défaut pour afficher quand même le lieu
 	return 20, 0, true
 }
+
+// LookupCoords retourne les coordonnées d'un lieu connu, sans position par défaut
+func LookupCoords(location string) (Coords, bool) {
+	key := strings.ToLower(strings.ReplaceAll(location, " ", "_"))
+	c, ok := cityCoords[key]
+	return c, ok
+}
@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultLanguage est la langue utilisée quand aucune langue demandée n'est disponible
+	DefaultLanguage = "fr"
+	// LanguageParam est le paramètre d'URL qui choisit la langue (?lang=en)
+	LanguageParam = "lang"
+	// LanguageCookie mémorise la langue choisie par ?lang=
+	LanguageCookie = "lang"
+)
+
+// Messages est le catalogue des messages de l'interface (installé au démarrage)
+var Messages *Catalog
+
+// Catalog regroupe les messages de chaque langue : un fichier JSON par langue ("fr.json"),
+// clés pointées ("error.artist_not_found") associées au texte, avec d'éventuels verbes fmt (%s, %d)
+type Catalog struct {
+	languages []string                     // Langue par défaut d'abord, puis ordre alphabétique
+	messages  map[string]map[string]string // langue -> clé -> message
+}
+
+// LoadCatalog lit les catalogues (*.json) de fsys. Chaque langue doit traduire exactement les
+// clés de la langue par défaut : un oubli est signalé au démarrage, pas devant l'utilisateur.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{messages: make(map[string]map[string]string)}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("catalogue %s : %w", name, err)
+		}
+		lang := strings.TrimSuffix(name, path.Ext(name))
+		c.messages[lang] = messages
+		if lang != DefaultLanguage {
+			c.languages = append(c.languages, lang)
+		}
+	}
+
+	reference, ok := c.messages[DefaultLanguage]
+	if !ok {
+		return nil, fmt.Errorf("catalogue de la langue par défaut (%s.json) manquant", DefaultLanguage)
+	}
+	sort.Strings(c.languages)
+	c.languages = append([]string{DefaultLanguage}, c.languages...)
+
+	var missing []string
+	for _, lang := range c.languages[1:] {
+		for key := range reference {
+			if _, ok := c.messages[lang][key]; !ok {
+				missing = append(missing, lang+":"+key)
+			}
+		}
+		for key := range c.messages[lang] {
+			if _, ok := reference[key]; !ok {
+				missing = append(missing, DefaultLanguage+":"+key)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("traductions manquantes : %s", strings.Join(missing, ", "))
+	}
+	return c, nil
+}
+
+// Languages retourne les langues disponibles, langue par défaut en premier
+func (c *Catalog) Languages() []string {
+	if c == nil {
+		return []string{DefaultLanguage}
+	}
+	return c.languages
+}
+
+// Supports indique si une langue est disponible
+func (c *Catalog) Supports(lang string) bool {
+	if c == nil {
+		return lang == DefaultLanguage
+	}
+	_, ok := c.messages[lang]
+	return ok
+}
+
+// Lookup retourne le message d'une clé dans une langue, sans repli
+func (c *Catalog) Lookup(lang, key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	msg, ok := c.messages[lang][key]
+	return msg, ok
+}
+
+// T traduit une clé ; args complète les verbes fmt du message. Une clé inconnue dans la langue
+// se replie sur la langue par défaut, puis sur la clé elle-même.
+func (c *Catalog) T(lang, key string, args ...interface{}) string {
+	msg, ok := c.Lookup(lang, key)
+	if !ok {
+		if msg, ok = c.Lookup(DefaultLanguage, key); !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Negotiate choisit la langue d'une requête : paramètre ?lang=, puis cookie, puis
+// Accept-Language (par qualité décroissante, "en-GB" acceptant "en"), sinon la langue par défaut
+func (c *Catalog) Negotiate(r *http.Request) string {
+	if lang := strings.ToLower(r.URL.Query().Get(LanguageParam)); c.Supports(lang) {
+		return lang
+	}
+	if cookie, err := r.Cookie(LanguageCookie); err == nil && c.Supports(cookie.Value) {
+		return cookie.Value
+	}
+	for _, tag := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+		if c.Supports(tag) {
+			return tag
+		}
+		if base, _, ok := strings.Cut(tag, "-"); ok && c.Supports(base) {
+			return base
+		}
+	}
+	return DefaultLanguage
+}
+
+// parseAcceptLanguage retourne les langues d'un en-tête Accept-Language ("fr-CH, fr;q=0.9, en;q=0.8"),
+// en minuscules, par qualité décroissante ; les langues refusées (q=0) et "*" sont ignorées
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// FormatNumber abrège un nombre selon la langue ("1,2 k" en français, "1.2K" en anglais).
+// Séparateur décimal et suffixes viennent du catalogue (number.decimal, number.thousands, number.millions).
+func (c *Catalog) FormatNumber(lang string, n int) string {
+	var value float64
+	var suffix string
+	switch {
+	case n < 1000:
+		return strconv.Itoa(n)
+	case n < 1000000:
+		value, suffix = float64(n)/1000, c.T(lang, "number.thousands")
+	default:
+		value, suffix = float64(n)/1000000, c.T(lang, "number.millions")
+	}
+	text := strconv.FormatFloat(value, 'f', 1, 64)
+	return strings.Replace(text, ".", c.T(lang, "number.decimal"), 1) + suffix
+}
+
+// languageKey est la clé de contexte de la langue d'une requête
+type languageKey struct{}
+
+// requestLanguage est la langue d'une requête ; used, s'il est défini, est appelé chaque fois
+// qu'elle sert à produire la réponse
+type requestLanguage struct {
+	lang string
+	used func()
+}
+
+// WithLanguage retourne un contexte portant la langue de la requête
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, requestLanguage{lang: lang})
+}
+
+// WithLocalizedResponse retourne un contexte portant la langue de la requête. La première
+// fois que Language la lit, w reçoit l'en-tête Vary (Accept-Language, Cookie) : seules les
+// réponses traduites varient selon la langue, les autres restent cachables par les proxys.
+// La langue doit être lue avant WriteHeader, comme tout en-tête.
+func WithLocalizedResponse(ctx context.Context, lang string, w http.ResponseWriter) context.Context {
+	var once sync.Once
+	used := func() {
+		once.Do(func() { w.Header().Add("Vary", "Accept-Language, Cookie") })
+	}
+	return context.WithValue(ctx, languageKey{}, requestLanguage{lang: lang, used: used})
+}
+
+// Language retourne la langue d'une requête : celle du contexte, sinon celle négociée
+func Language(r *http.Request) string {
+	if l, ok := r.Context().Value(languageKey{}).(requestLanguage); ok {
+		if l.used != nil {
+			l.used()
+		}
+		return l.lang
+	}
+	return Messages.Negotiate(r)
+}
+
+// T traduit une clé dans la langue de la requête
+func T(r *http.Request, key string, args ...interface{}) string {
+	return Messages.T(Language(r), key, args...)
+}
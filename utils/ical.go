@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"groupie-tracker-ng/models"
+)
+
+// ICalEvent est un concert d'un calendrier iCalendar (RFC 5545), sur une journée entière
+type ICalEvent struct {
+	UID      string
+	Date     time.Time
+	Summary  string
+	Location string
+	Geo      *Coords // nil si le lieu n'a pas de coordonnées connues
+	URL      string
+}
+
+// ICalendar est un calendrier de concerts
+type ICalendar struct {
+	Name   string
+	Events []ICalEvent
+}
+
+// concertDateLayout est le format des dates de concerts Groupie ("23-08-2019")
+const concertDateLayout = "02-01-2006"
+
+// ConcertTime convertit une date de concert Groupie ("23-08-2019", "*23-08-2019") en date
+func ConcertTime(date string) (time.Time, bool) {
+	t, err := time.Parse(concertDateLayout, strings.TrimPrefix(strings.TrimSpace(date), "*"))
+	return t, err == nil
+}
+
+// NewConcertCalendar construit le calendrier des concerts des artistes, à partir de leurs
+// relations dates-lieux. keep filtre les lieux (tous si nil) ; baseURL (origine du site)
+// sert aux liens vers les pages des artistes.
+func NewConcertCalendar(name, baseURL string, artists []models.ArtistDetail, keep func(location string) bool) ICalendar {
+	cal := ICalendar{Name: name, Events: []ICalEvent{}}
+	for _, artist := range artists {
+		for location, dates := range artist.Relations {
+			if keep != nil && !keep(location) {
+				continue
+			}
+			label := LocationLabel(location)
+			var geo *Coords
+			if c, ok := LookupCoords(location); ok {
+				geo = &c
+			}
+			for _, date := range dates {
+				day, ok := ConcertTime(date)
+				if !ok {
+					continue
+				}
+				cal.Events = append(cal.Events, ICalEvent{
+					UID:      concertUID(artist.Artist, location, day),
+					Date:     day,
+					Summary:  artist.Name + " — " + label,
+					Location: label,
+					Geo:      geo,
+					URL:      artistPermalink(baseURL, artist.Artist),
+				})
+			}
+		}
+	}
+
+	sort.Slice(cal.Events, func(i, j int) bool {
+		a, b := cal.Events[i], cal.Events[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.UID < b.UID
+	})
+	return cal
+}
+
+// artistPermalink retourne l'adresse stable de la page d'un artiste (par ID Spotify, vide sans) :
+// un calendrier garde ses liens d'un rafraîchissement du catalogue à l'autre
+func artistPermalink(baseURL string, artist models.Artist) string {
+	if artist.SpotifyID == "" {
+		return ""
+	}
+	return baseURL + ArtistPermalinkPath(artist.SpotifyID)
+}
+
+// ArtistPermalinkPath retourne le chemin stable de la page d'un artiste (/spotify/artist/{spotifyId}),
+// qui redirige vers sa page du catalogue
+func ArtistPermalinkPath(spotifyID string) string {
+	return "/spotify/artist/" + url.PathEscape(spotifyID)
+}
+
+// concertUID identifie un concert (artiste, lieu, jour) indépendamment de l'hôte et de l'ordre
+// des données : un client de calendrier met à jour l'événement au lieu de le dupliquer.
+// L'artiste est désigné par son ID Spotify (par son nom à défaut) : l'ID du catalogue change
+// d'un rafraîchissement à l'autre.
+func concertUID(artist models.Artist, location string, day time.Time) string {
+	artistKey := artist.SpotifyID
+	if artistKey == "" {
+		artistKey = strings.ReplaceAll(FoldText(artist.Name), " ", "-")
+	}
+	slug := strings.ReplaceAll(NormalizeLocation(location), " ", "-")
+	return fmt.Sprintf("concert-%s-%s-%s@groupie-tracker", artistKey, slug, day.Format("20060102"))
+}
+
+// WriteICalendar encode le calendrier (lignes CRLF, repliées à 75 octets) ; stamp est la date
+// de génération (DTSTAMP)
+func WriteICalendar(w io.Writer, cal ICalendar, stamp time.Time) error {
+	iw := &icalWriter{w: w}
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//Groupie Tracker//Concerts//FR")
+	iw.line("CALSCALE:GREGORIAN")
+	iw.line("METHOD:PUBLISH")
+	iw.line("X-WR-CALNAME:" + icalText(cal.Name))
+
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, e := range cal.Events {
+		iw.line("BEGIN:VEVENT")
+		iw.line("UID:" + icalText(e.UID))
+		iw.line("DTSTAMP:" + dtstamp)
+		iw.line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+		iw.line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+		iw.line("SUMMARY:" + icalText(e.Summary))
+		iw.line("LOCATION:" + icalText(e.Location))
+		if e.Geo != nil {
+			iw.line(fmt.Sprintf("GEO:%.6f;%.6f", e.Geo.Lat, e.Geo.Lng))
+		}
+		if e.URL != "" {
+			iw.line("URL:" + e.URL)
+		}
+		iw.line("TRANSP:TRANSPARENT")
+		iw.line("END:VEVENT")
+	}
+	iw.line("END:VCALENDAR")
+	return iw.err
+}
+
+// icalWriter écrit des lignes de contenu en gardant la première erreur
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line écrit une ligne repliée : au-delà de 75 octets, la suite passe à la ligne
+// précédée d'une espace, sans couper un caractère UTF-8
+func (iw *icalWriter) line(s string) {
+	if iw.err != nil {
+		return
+	}
+	var sb strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += size
+	}
+	sb.WriteString("\r\n")
+	_, iw.err = io.WriteString(iw.w, sb.String())
+}
+
+// icalTextReplacer échappe les caractères réservés des valeurs TEXT
+var icalTextReplacer = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icalText échappe une valeur TEXT (antislash, point-virgule, virgule, retours à la ligne)
+func icalText(s string) string {
+	return icalTextReplacer.Replace(s)
+}
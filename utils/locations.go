@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	"groupie-tracker-ng/models"
+)
+
+// LocationIndex est un index inversé lieu normalisé -> IDs d'artistes,
+// construit à partir des relations dates-lieux.
+type LocationIndex struct {
+	artists map[string][]int    // clé normalisée ("paris france") -> IDs triés
+	labels  map[string]string   // clé normalisée -> libellé affichable ("Paris, France")
+	cities  map[string][]string // ville normalisée ("paris") -> clés complètes
+}
+
+// NormalizeLocation ramène un lieu à une forme canonique pour la comparaison.
+// "new_york-usa", "New York, USA" et "new york usa" donnent tous "new york usa" ;
+// les accents sont ignorés ("Saint-Étienne" = "saint etienne").
+func NormalizeLocation(location string) string {
+	return FoldText(location)
+}
+
+// LocationLabel transforme un lieu Groupie ("new_york-usa") en libellé ("New York, USA")
+func LocationLabel(location string) string {
+	parts := strings.SplitN(strings.ToLower(strings.TrimSpace(location)), "-", 2)
+	city := titleWords(strings.ReplaceAll(parts[0], "_", " "))
+	if len(parts) == 1 {
+		return city
+	}
+	country := strings.ReplaceAll(parts[1], "_", " ")
+	if len(country) <= 3 {
+		country = strings.ToUpper(country)
+	} else {
+		country = titleWords(country)
+	}
+	return city + ", " + country
+}
+
+// titleWords met en majuscule la première lettre de chaque mot
+func titleWords(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r := []rune(w)
+		words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+// BuildLocationIndex construit l'index des lieux de concerts à partir des relations
+func BuildLocationIndex(relations []models.Relation) *LocationIndex {
+	index := &LocationIndex{
+		artists: make(map[string][]int),
+		labels:  make(map[string]string),
+		cities:  make(map[string][]string),
+	}
+
+	for _, relation := range relations {
+		for location := range relation.DatesLocations {
+			key := NormalizeLocation(location)
+			if key == "" {
+				continue
+			}
+			if _, ok := index.labels[key]; !ok {
+				index.labels[key] = LocationLabel(location)
+				city := NormalizeLocation(strings.SplitN(location, "-", 2)[0])
+				if city != key {
+					index.cities[city] = append(index.cities[city], key)
+				}
+			}
+			index.artists[key] = append(index.artists[key], relation.ID)
+		}
+	}
+
+	// Trier et dédoublonner les IDs pour des résultats stables
+	for key, ids := range index.artists {
+		sort.Ints(ids)
+		unique := ids[:0]
+		for i, id := range ids {
+			if i == 0 || id != ids[i-1] {
+				unique = append(unique, id)
+			}
+		}
+		index.artists[key] = unique
+	}
+
+	return index
+}
+
+// ArtistIDs retourne l'ensemble des artistes ayant joué dans l'un des lieux demandés.
+// Un lieu peut être désigné par sa forme complète ("paris-france") ou par la ville seule ("Paris").
+func (idx *LocationIndex) ArtistIDs(locations []string) map[int]bool {
+	ids := make(map[int]bool)
+	if idx == nil {
+		return ids
+	}
+
+	for _, location := range locations {
+		wanted := NormalizeLocation(location)
+		if wanted == "" {
+			continue
+		}
+		keys := idx.cities[wanted]
+		if _, ok := idx.artists[wanted]; ok {
+			keys = append([]string{wanted}, keys...)
+		}
+		for _, key := range keys {
+			for _, id := range idx.artists[key] {
+				ids[id] = true
+			}
+		}
+	}
+
+	return ids
+}
+
+// Len retourne le nombre de lieux distincts indexés
+func (idx *LocationIndex) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.artists)
+}
+
+// Popular retourne les libellés des lieux, du plus fréquenté au moins fréquenté
+func (idx *LocationIndex) Popular() []string {
+	if idx == nil {
+		return []string{}
+	}
+
+	keys := make([]string, 0, len(idx.artists))
+	for key := range idx.artists {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := len(idx.artists[keys[i]]), len(idx.artists[keys[j]])
+		if ci != cj {
+			return ci > cj
+		}
+		return idx.labels[keys[i]] < idx.labels[keys[j]]
+	})
+
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = idx.labels[key]
+	}
+	return labels
+}
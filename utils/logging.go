@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// RequestIDHeader est l'en-tête qui porte l'identifiant de requête (accepté en entrée, renvoyé en réponse)
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey est la clé de contexte de l'identifiant de requête
+type requestIDKey struct{}
+
+// NewRequestID génère un identifiant de requête aléatoire (16 caractères hexadécimaux)
+func NewRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ValidRequestID indique si un identifiant reçu d'un client ou d'un proxy peut être repris tel quel
+// (1 à 64 caractères parmi lettres, chiffres, "-", "_" et ".") : il finit dans les journaux
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRequestID retourne un contexte portant l'identifiant de requête
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID retourne l'identifiant de requête du contexte ("" si aucun)
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Logger retourne le journal structuré du contexte : chaque ligne porte l'identifiant
+// de la requête à l'origine du travail (request_id), s'il y en a un
+func Logger(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
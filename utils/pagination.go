@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"net/url"
+	"strconv"
+
+	"groupie-tracker-ng/models"
+)
+
+const (
+	// DefaultPerPage est le nombre d'artistes par page si "perPage" est absent
+	DefaultPerPage = 24
+	// MaxPerPage borne le paramètre "perPage"
+	MaxPerPage = 100
+)
+
+// PageLink représente un lien de pagination
+type PageLink struct {
+	Number  int
+	URL     string
+	Current bool
+	Gap     bool // "…" entre deux plages de pages
+}
+
+// Pagination décrit la page courante d'une liste d'artistes
+type Pagination struct {
+	Page       int        `json:"page"`
+	PerPage    int        `json:"perPage"`
+	Total      int        `json:"total"` // Nombre total d'artistes (tous filtres appliqués)
+	TotalPages int        `json:"totalPages"`
+	From       int        `json:"from"` // Position (1-based) du premier artiste affiché
+	To         int        `json:"to"`   // Position du dernier artiste affiché
+	PrevURL    string     `json:"prev,omitempty"`
+	NextURL    string     `json:"next,omitempty"`
+	Pages      []PageLink `json:"-"`
+}
+
+// Paginate découpe la liste selon options.Page / options.PerPage.
+// baseURL porte les paramètres actifs (filtres, tri, recherche) recopiés dans chaque lien.
+func Paginate(artists []models.Artist, options models.FilterOptions, baseURL *url.URL) ([]models.Artist, Pagination) {
+	perPage := options.PerPage
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+
+	total := len(artists)
+	totalPages := (total + perPage - 1) / perPage
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	page := options.Page
+	if page < 1 {
+		page = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+
+	start := (page - 1) * perPage
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	p := Pagination{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: totalPages,
+		To:         end,
+	}
+	if total > 0 {
+		p.From = start + 1
+	}
+
+	if baseURL != nil {
+		if page > 1 {
+			p.PrevURL = pageURL(baseURL, page-1)
+		}
+		if page < totalPages {
+			p.NextURL = pageURL(baseURL, page+1)
+		}
+		if totalPages > 1 {
+			p.Pages = pageLinks(baseURL, page, totalPages)
+		}
+	}
+
+	return artists[start:end], p
+}
+
+// pageLinks génère les liens autour de la page courante (première, dernière et ±2)
+func pageLinks(baseURL *url.URL, current, totalPages int) []PageLink {
+	links := []PageLink{}
+	last := 0
+	for n := 1; n <= totalPages; n++ {
+		if n != 1 && n != totalPages && (n < current-2 || n > current+2) {
+			continue
+		}
+		if last > 0 && n > last+1 {
+			links = append(links, PageLink{Gap: true})
+		}
+		links = append(links, PageLink{
+			Number:  n,
+			URL:     pageURL(baseURL, n),
+			Current: n == current,
+		})
+		last = n
+	}
+	return links
+}
+
+// pageURL recopie l'URL courante (filtres compris) en changeant uniquement le numéro de page
+func pageURL(baseURL *url.URL, page int) string {
+	query := baseURL.Query()
+	if page <= 1 {
+		query.Del("page")
+	} else {
+		query.Set("page", strconv.Itoa(page))
+	}
+	u := url.URL{Path: baseURL.Path, RawQuery: query.Encode()}
+	return u.String()
+}
@@ -0,0 +1,400 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"groupie-tracker-ng/models"
+)
+
+// QueryError décrit une erreur de syntaxe dans la requête de recherche
+type QueryError struct {
+	Position int           // Position (en caractères, à partir de 0) du terme fautif
+	Term     string        // Terme fautif tel que saisi
+	Key      string        // Clé du message dans le catalogue ("query.unclosed_quote")
+	Args     []interface{} // Arguments du message
+}
+
+func (e QueryError) Error() string {
+	return e.Localize(DefaultLanguage)
+}
+
+// Message retourne l'explication de l'erreur dans une langue
+func (e QueryError) Message(lang string) string {
+	return Messages.T(lang, e.Key, e.Args...)
+}
+
+// Localize retourne l'erreur complète (terme, position, explication) dans une langue
+func (e QueryError) Localize(lang string) string {
+	return Messages.T(lang, "query.term_error", e.Term, e.Column(), e.Message(lang))
+}
+
+// Column retourne la position du terme fautif à partir de 1 (affichage)
+func (e QueryError) Column() int {
+	return e.Position + 1
+}
+
+// ParsedQuery est le résultat de l'analyse d'une requête structurée, par exemple :
+//
+//	genre:rock year:>=2000 members:3 popularity:>70 "exact phrase" -excluded
+type ParsedQuery struct {
+	Text     string               // Mots libres (recherche approchée et classée)
+	Phrases  []string             // Expressions exactes entre guillemets
+	Excluded []string             // Termes exclus (préfixe "-")
+	Options  models.FilterOptions // Filtres du formulaire complétés par ceux de la requête
+	Errors   []QueryError
+}
+
+// queryTerm est un terme brut de la requête
+type queryTerm struct {
+	pos     int
+	raw     string
+	negated bool
+	key     string // Vide pour un mot libre
+	value   string
+	quoted  bool
+}
+
+// QueryKeys liste les filtres reconnus dans la barre de recherche
+var QueryKeys = []string{"genre", "location", "year", "members", "popularity", "firstalbum"}
+
+// ParseQuery analyse une requête structurée et fusionne ses filtres avec options (formulaire)
+func ParseQuery(query string, options models.FilterOptions) ParsedQuery {
+	parsed := ParsedQuery{Options: options}
+	terms, errs := tokenizeQuery(query)
+	parsed.Errors = append(parsed.Errors, errs...)
+
+	var words []string
+	for _, term := range terms {
+		switch {
+		case term.key != "":
+			if term.negated {
+				parsed.Errors = append(parsed.Errors, QueryError{term.pos, term.raw, "query.exclusion_words_only", nil})
+				continue
+			}
+			if err := applyQueryFilter(&parsed.Options, term); err != "" {
+				parsed.Errors = append(parsed.Errors, QueryError{term.pos, term.raw, err, nil})
+			}
+		case term.negated:
+			parsed.Excluded = append(parsed.Excluded, term.value)
+		case term.quoted:
+			parsed.Phrases = append(parsed.Phrases, term.value)
+		default:
+			words = append(words, term.value)
+		}
+	}
+	parsed.Text = strings.Join(words, " ")
+
+	return parsed
+}
+
+// tokenizeQuery découpe la requête en termes (mots, "expressions", -exclusions, clé:valeur)
+func tokenizeQuery(query string) ([]queryTerm, []QueryError) {
+	runes := []rune(query)
+	var terms []queryTerm
+	var errs []QueryError
+
+	i := 0
+terms:
+	for i < len(runes) {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		term := queryTerm{pos: i}
+		start := i
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			term.negated = true
+			i++
+		}
+
+		// Lire la clé éventuelle puis la valeur (éventuellement entre guillemets)
+		var value []rune
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			r := runes[i]
+			if r == ':' && term.key == "" && !term.quoted && len(value) > 0 {
+				term.key = strings.ToLower(string(value))
+				value = value[:0]
+				i++
+				continue
+			}
+			if r == '"' && len(value) == 0 && !term.quoted {
+				end := indexRune(runes, '"', i+1)
+				if end < 0 {
+					errs = append(errs, QueryError{start, string(runes[start:]), "query.unclosed_quote", nil})
+					break terms
+				}
+				value = append(value, runes[i+1:end]...)
+				term.quoted = true
+				i = end + 1
+				continue
+			}
+			value = append(value, r)
+			i++
+		}
+
+		term.raw = string(runes[start:i])
+		term.value = strings.TrimSpace(string(value))
+		if term.value == "" {
+			if term.key != "" {
+				errs = append(errs, QueryError{start, term.raw, "query.missing_value", []interface{}{term.key}})
+			}
+			continue
+		}
+		if term.key != "" && !isQueryKey(term.key) {
+			errs = append(errs, QueryError{start, term.raw, "query.unknown_filter", []interface{}{strings.Join(QueryKeys, ", ")}})
+			continue
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, errs
+}
+
+// indexRune cherche r à partir de la position from
+func indexRune(runes []rune, r rune, from int) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// isQueryKey vérifie qu'une clé fait partie des filtres reconnus
+func isQueryKey(key string) bool {
+	for _, k := range QueryKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// applyQueryFilter reporte un filtre clé:valeur dans les options ; retourne la clé du message d'erreur sinon
+func applyQueryFilter(options *models.FilterOptions, term queryTerm) string {
+	switch term.key {
+	case "genre":
+		options.Genres = append(options.Genres, term.value)
+	case "location":
+		options.Locations = append(options.Locations, term.value)
+	case "year":
+		r, err := parseIntComparison(term.value)
+		if err != "" {
+			return err
+		}
+		if r.hasMin {
+			options.MinYear = r.min
+		}
+		if r.hasMax {
+			options.MaxYear = r.max
+		}
+	case "popularity":
+		r, err := parseIntComparison(term.value)
+		if err != "" {
+			return err
+		}
+		if r.hasMin && (r.min < 0 || r.min > 100) {
+			return "query.popularity_min"
+		}
+		if r.hasMax && (r.max < 1 || r.max > 100) {
+			return "query.popularity_max"
+		}
+		if r.hasMin {
+			options.MinPopularity = r.min
+		}
+		if r.hasMax {
+			options.MaxPopularity = r.max
+		}
+	case "members":
+		r, err := parseIntComparison(term.value)
+		if err != "" {
+			return err
+		}
+		counts := memberCountsInRange(r)
+		if len(counts) == 0 {
+			return "query.members_min"
+		}
+		options.MemberCount = counts
+	case "firstalbum":
+		from, to, err := parseDateComparison(term.value)
+		if err != "" {
+			return err
+		}
+		if from != "" {
+			options.FirstAlbumMin = from
+		}
+		if to != "" {
+			options.FirstAlbumMax = to
+		}
+	}
+	return ""
+}
+
+// intRange est un intervalle d'entiers dont chaque borne est facultative
+type intRange struct {
+	min, max       int
+	hasMin, hasMax bool
+}
+
+// parseIntComparison interprète "2000", ">=2000", ">2000", "<=2000", "<2000" ou "1990..2000"
+func parseIntComparison(value string) (intRange, string) {
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		from, err := strconv.Atoi(lo)
+		if err != nil {
+			return intRange{}, "query.number_before_range"
+		}
+		to, err := strconv.Atoi(hi)
+		if err != nil {
+			return intRange{}, "query.number_after_range"
+		}
+		if from > to {
+			return intRange{}, "query.inverted_range"
+		}
+		return intRange{from, to, true, true}, ""
+	}
+
+	op, number := splitComparison(value)
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return intRange{}, "query.number_expected"
+	}
+
+	switch op {
+	case ">=":
+		return intRange{min: n, hasMin: true}, ""
+	case ">":
+		return intRange{min: n + 1, hasMin: true}, ""
+	case "<=":
+		return intRange{max: n, hasMax: true}, ""
+	case "<":
+		return intRange{max: n - 1, hasMax: true}, ""
+	default:
+		return intRange{n, n, true, true}, ""
+	}
+}
+
+// parseDateComparison interprète une date (YYYY, YYYY-MM ou YYYY-MM-DD) précédée d'un opérateur.
+// Une date partielle désigne toute l'année ou tout le mois : "2010" va du 1er janvier au
+// 31 décembre, ">2010" commence en 2011. Les bornes retournées sont des jours (YYYY-MM-DD).
+func parseDateComparison(value string) (from, to, errKey string) {
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		start, err := dateBound(lo, false)
+		if err != nil {
+			return "", "", "query.date_before_range"
+		}
+		end, err := dateBound(hi, true)
+		if err != nil {
+			return "", "", "query.date_after_range"
+		}
+		if start.After(end) {
+			return "", "", "query.inverted_range"
+		}
+		return start.Format(dayLayout), end.Format(dayLayout), ""
+	}
+
+	op, date := splitComparison(value)
+	start, err := dateBound(date, false)
+	if err != nil {
+		return "", "", "query.date_expected"
+	}
+	end, _ := dateBound(date, true)
+
+	switch op {
+	case ">=":
+		return start.Format(dayLayout), "", ""
+	case ">":
+		return end.AddDate(0, 0, 1).Format(dayLayout), "", ""
+	case "<=":
+		return "", end.Format(dayLayout), ""
+	case "<":
+		return "", start.AddDate(0, 0, -1).Format(dayLayout), ""
+	default:
+		return start.Format(dayLayout), end.Format(dayLayout), ""
+	}
+}
+
+// splitComparison sépare l'opérateur (>=, <=, >, <, =) de la valeur
+func splitComparison(value string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, strings.TrimPrefix(value, op)
+		}
+	}
+	return "", value
+}
+
+// memberCountsInRange convertit un intervalle en valeurs du filtre "membres" (5 = 5 et plus)
+func memberCountsInRange(r intRange) []int {
+	from, to := 1, 5
+	if r.hasMin {
+		from = r.min
+	}
+	if r.hasMax && r.max < 5 {
+		to = r.max
+	}
+	if from < 1 {
+		from = 1
+	}
+	if from > 5 {
+		from = 5
+	}
+
+	counts := []int{}
+	for n := from; n <= to; n++ {
+		counts = append(counts, n)
+	}
+	return counts
+}
+
+// ApplyQuery applique la partie textuelle d'une requête analysée : mots libres (recherche classée),
+// expressions exactes et exclusions. Les filtres (Options) sont appliqués séparément par FilterArtists.
+func ApplyQuery(artists []models.Artist, parsed ParsedQuery) []models.Artist {
+	if parsed.Text != "" {
+		artists = SearchArtists(artists, parsed.Text)
+	}
+	if len(parsed.Phrases) == 0 && len(parsed.Excluded) == 0 {
+		return artists
+	}
+
+	filtered := make([]models.Artist, 0, len(artists))
+	for _, artist := range artists {
+		text := " " + searchableText(artist) + " "
+		keep := true
+		for _, phrase := range parsed.Phrases {
+			// Expression vide une fois repliée ("!!") : pas de filtre, comme pour le texte libre
+			if folded := FoldText(phrase); folded != "" && !strings.Contains(text, " "+folded+" ") {
+				keep = false
+				break
+			}
+		}
+		for _, excluded := range parsed.Excluded {
+			if !keep {
+				break
+			}
+			// Mots entiers : "-rap" n'écarte pas "Trap", "-ed" n'écarte pas "Red Hot Chili Peppers"
+			if folded := FoldText(excluded); folded != "" && strings.Contains(text, " "+folded+" ") {
+				keep = false
+			}
+		}
+		if keep {
+			filtered = append(filtered, artist)
+		}
+	}
+
+	return filtered
+}
+
+// searchableText concatène les champs texte d'un artiste, repliés et séparés par " | "
+func searchableText(artist models.Artist) string {
+	fields := []string{FoldText(artist.Name), FoldText(artist.FirstAlbum)}
+	for _, member := range artist.Members {
+		fields = append(fields, FoldText(member))
+	}
+	for _, genre := range artist.Genres {
+		fields = append(fields, FoldText(genre))
+	}
+	return strings.Join(fields, " | ")
+}
@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"groupie-tracker-ng/models"
+)
+
+func TestTokenizeQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		terms []queryTerm
+		errs  []QueryError
+	}{
+		{
+			query: `genre:rock year:>=2000 "exact phrase" -excluded free`,
+			terms: []queryTerm{
+				{pos: 0, raw: "genre:rock", key: "genre", value: "rock"},
+				{pos: 11, raw: "year:>=2000", key: "year", value: ">=2000"},
+				{pos: 23, raw: `"exact phrase"`, value: "exact phrase", quoted: true},
+				{pos: 38, raw: "-excluded", negated: true, value: "excluded"},
+				{pos: 48, raw: "free", value: "free"},
+			},
+		},
+		{
+			query: `Genre:"hip hop"`,
+			terms: []queryTerm{{pos: 0, raw: `Genre:"hip hop"`, key: "genre", value: "hip hop", quoted: true}},
+		},
+		{
+			query: "a - b",
+			terms: []queryTerm{
+				{pos: 0, raw: "a", value: "a"},
+				{pos: 2, raw: "-", value: "-"},
+				{pos: 4, raw: "b", value: "b"},
+			},
+		},
+		{
+			query: `rock "unclosed phrase`,
+			terms: []queryTerm{{pos: 0, raw: "rock", value: "rock"}},
+			errs:  []QueryError{{Position: 5, Term: `"unclosed phrase`, Key: "query.unclosed_quote"}},
+		},
+		{
+			query: "year: rock",
+			terms: []queryTerm{{pos: 6, raw: "rock", value: "rock"}},
+			errs:  []QueryError{{Position: 0, Term: "year:", Key: "query.missing_value"}},
+		},
+		{
+			// Positions en caractères, pas en octets
+			query: "été colour:red",
+			errs:  []QueryError{{Position: 4, Term: "colour:red", Key: "query.unknown_filter"}},
+			terms: []queryTerm{{pos: 0, raw: "été", value: "été"}},
+		},
+	}
+
+	for _, tt := range tests {
+		terms, errs := tokenizeQuery(tt.query)
+		if !reflect.DeepEqual(terms, tt.terms) {
+			t.Errorf("tokenizeQuery(%q) termes = %+v, attendu %+v", tt.query, terms, tt.terms)
+		}
+		if !sameQueryErrors(errs, tt.errs) {
+			t.Errorf("tokenizeQuery(%q) erreurs = %+v, attendu %+v", tt.query, errs, tt.errs)
+		}
+	}
+}
+
+func TestParseIntComparison(t *testing.T) {
+	tests := []struct {
+		value string
+		want  intRange
+		err   string
+	}{
+		{"2000", intRange{2000, 2000, true, true}, ""},
+		{"=4", intRange{4, 4, true, true}, ""},
+		{">=2000", intRange{min: 2000, hasMin: true}, ""},
+		{">70", intRange{min: 71, hasMin: true}, ""},
+		{"<=5", intRange{max: 5, hasMax: true}, ""},
+		{"<3", intRange{max: 2, hasMax: true}, ""},
+		{"1990..2000", intRange{1990, 2000, true, true}, ""},
+		{"2000..1990", intRange{}, "query.inverted_range"},
+		{"x..2000", intRange{}, "query.number_before_range"},
+		{"1990..y", intRange{}, "query.number_after_range"},
+		{">=abc", intRange{}, "query.number_expected"},
+		{"", intRange{}, "query.number_expected"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseIntComparison(tt.value)
+		if got != tt.want || err != tt.err {
+			t.Errorf("parseIntComparison(%q) = %+v, %q ; attendu %+v, %q", tt.value, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParseDateComparison(t *testing.T) {
+	tests := []struct {
+		value, from, to, err string
+	}{
+		{"2005", "2005-01-01", "2005-12-31", ""},
+		{"2004-02", "2004-02-01", "2004-02-29", ""},
+		{"15-06-2005", "2005-06-15", "2005-06-15", ""},
+		{">=2005-06", "2005-06-01", "", ""},
+		{">2005", "2006-01-01", "", ""},
+		{">2005-06-30", "2005-07-01", "", ""},
+		{"<=2005", "", "2005-12-31", ""},
+		{"<2005-06-01", "", "2005-05-31", ""},
+		{"<2005", "", "2004-12-31", ""},
+		{"2000..2005", "2000-01-01", "2005-12-31", ""},
+		{"2005..2005-03", "2005-01-01", "2005-03-31", ""},
+		{"2005..2000", "", "", "query.inverted_range"},
+		{"2005-06..2005-05-31", "", "", "query.inverted_range"},
+		{"bad..2005", "", "", "query.date_before_range"},
+		{"2000..bad", "", "", "query.date_after_range"},
+		{">soon", "", "", "query.date_expected"},
+	}
+
+	for _, tt := range tests {
+		from, to, err := parseDateComparison(tt.value)
+		if from != tt.from || to != tt.to || err != tt.err {
+			t.Errorf("parseDateComparison(%q) = %q, %q, %q ; attendu %q, %q, %q",
+				tt.value, from, to, err, tt.from, tt.to, tt.err)
+		}
+	}
+}
+
+func TestParseQueryErrorPositions(t *testing.T) {
+	parsed := ParseQuery("rock popularity:200 -genre:pop members:0 year:1990..", models.FilterOptions{})
+
+	want := []QueryError{
+		{Position: 5, Term: "popularity:200", Key: "query.popularity_min"},
+		{Position: 20, Term: "-genre:pop", Key: "query.exclusion_words_only"},
+		{Position: 31, Term: "members:0", Key: "query.members_min"},
+		{Position: 41, Term: "year:1990..", Key: "query.number_after_range"},
+	}
+	if !sameQueryErrors(parsed.Errors, want) {
+		t.Errorf("erreurs = %+v, attendu %+v", parsed.Errors, want)
+	}
+	if parsed.Text != "rock" {
+		t.Errorf("texte = %q, attendu %q", parsed.Text, "rock")
+	}
+	for _, e := range parsed.Errors {
+		if e.Column() != e.Position+1 {
+			t.Errorf("colonne de %q = %d, attendu %d", e.Term, e.Column(), e.Position+1)
+		}
+	}
+}
+
+func TestParseQueryFilters(t *testing.T) {
+	parsed := ParseQuery(`genre:rock year:>=2000 members:3 popularity:>70 firstalbum:<2005 "exact phrase" -excluded free words`,
+		models.FilterOptions{Genres: []string{"pop"}})
+
+	want := models.FilterOptions{
+		Genres:        []string{"pop", "rock"},
+		MinYear:       2000,
+		MemberCount:   []int{3},
+		MinPopularity: 71,
+		FirstAlbumMax: "2004-12-31",
+	}
+	if !reflect.DeepEqual(parsed.Options, want) {
+		t.Errorf("options = %+v, attendu %+v", parsed.Options, want)
+	}
+	if parsed.Text != "free words" {
+		t.Errorf("texte = %q", parsed.Text)
+	}
+	if !reflect.DeepEqual(parsed.Phrases, []string{"exact phrase"}) || !reflect.DeepEqual(parsed.Excluded, []string{"excluded"}) {
+		t.Errorf("expressions = %q, exclusions = %q", parsed.Phrases, parsed.Excluded)
+	}
+	if len(parsed.Errors) != 0 {
+		t.Errorf("erreurs inattendues : %+v", parsed.Errors)
+	}
+}
+
+func TestApplyQueryExcludesWholeWords(t *testing.T) {
+	artists := []models.Artist{
+		{ID: 1, Name: "Red Hot Chili Peppers"},
+		{ID: 2, Name: "Trapper", Genres: []string{"trap"}},
+		{ID: 3, Name: "Ed Sheeran"},
+		{ID: 4, Name: "Beyoncé", Genres: []string{"r&b"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"-ed", []int{1, 2, 4}},
+		{"-rap", []int{1, 2, 3, 4}},
+		{"-trap", []int{1, 3, 4}},
+		{"-beyonce", []int{1, 2, 3}},
+		{`-"chili peppers"`, []int{2, 3, 4}},
+		{`"hot chili"`, []int{1}},
+		{`"hot chil"`, []int{}},
+	}
+
+	for _, tt := range tests {
+		got := []int{}
+		for _, artist := range ApplyQuery(artists, ParseQuery(tt.query, models.FilterOptions{})) {
+			got = append(got, artist.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ApplyQuery(%q) = %v, attendu %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestApplyQueryText(t *testing.T) {
+	artists := []models.Artist{
+		{ID: 1, Name: "Creedence Clearwater Revival"},
+		{ID: 2, Name: "Queen"},
+		{ID: 3, Name: "Daft Punk"},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		// Une à deux fautes par mot, cinq au total : reste une correspondance
+		{"creedense claerwatter revivle", []int{1}},
+		{"quen", []int{2}},
+		// Ponctuation seule : aucun filtre texte
+		{"!!", []int{1, 2, 3}},
+		{"-", []int{1, 2, 3}},
+		{`"!!" daft`, []int{3}},
+	}
+
+	for _, tt := range tests {
+		got := []int{}
+		for _, artist := range ApplyQuery(artists, ParseQuery(tt.query, models.FilterOptions{})) {
+			got = append(got, artist.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ApplyQuery(%q) = %v, attendu %v", tt.query, got, tt.want)
+		}
+	}
+
+	if m := MatchText("creedence clearwater revival", "creedense claerwatter revivle"); m.Kind != MatchFuzzy || m.Quality() <= 0 {
+		t.Errorf("correspondance approchée = %+v, qualité %v ; attendu une qualité positive", m, m.Quality())
+	}
+}
+
+func TestFilterArtistsFirstAlbum(t *testing.T) {
+	artists := []models.Artist{
+		{ID: 1, Name: "A", FirstAlbumDate: "2009-11-20"},
+		{ID: 2, Name: "B", FirstAlbumDate: "2010"},
+		{ID: 3, Name: "C", FirstAlbumDate: "2010-06-15"},
+		{ID: 4, Name: "D", FirstAlbumDate: "2010-12-31"},
+		{ID: 5, Name: "E", FirstAlbumDate: "2011-01-01"},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"firstalbum:2010", []int{2, 3, 4}},
+		{"firstalbum:2010-06", []int{3}},
+		{"firstalbum:<=2010", []int{1, 2, 3, 4}},
+		{"firstalbum:<2010", []int{1}},
+		{"firstalbum:>2009", []int{2, 3, 4, 5}},
+		{"firstalbum:>2010-06-15", []int{4, 5}},
+		{"firstalbum:>=2010-06", []int{3, 4, 5}},
+		{"firstalbum:2009-11..2010-06", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		parsed := ParseQuery(tt.query, models.FilterOptions{})
+		if len(parsed.Errors) != 0 {
+			t.Errorf("%q : erreurs %+v", tt.query, parsed.Errors)
+			continue
+		}
+		got := []int{}
+		for _, artist := range FilterArtists(artists, parsed.Options, nil) {
+			got = append(got, artist.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FilterArtists(%q) = %v, attendu %v", tt.query, got, tt.want)
+		}
+	}
+
+	// Borne haute venue du formulaire (paramètre firstAlbumMax) : même lecture que la requête
+	got := []int{}
+	for _, artist := range FilterArtists(artists, models.FilterOptions{FirstAlbumMax: "2010"}, nil) {
+		got = append(got, artist.ID)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t.Errorf("FilterArtists(firstAlbumMax=2010) = %v, attendu [1 2 3 4]", got)
+	}
+}
+
+// sameQueryErrors compare la position, le terme et la clé de message des erreurs
+func sameQueryErrors(got, want []QueryError) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i].Position != want[i].Position || got[i].Term != want[i].Term || got[i].Key != want[i].Key {
+			return false
+		}
+	}
+	return true
+}
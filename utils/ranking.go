@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"math"
+)
+
+// RankingWeights règle le classement des suggestions : le type de correspondance
+// donne une note de base, corrigée par les fautes de frappe et la notoriété de l'artiste
+type RankingWeights struct {
+	Exact        float64 `json:"exact"`        // Texte identique
+	Prefix       float64 `json:"prefix"`       // Début du texte
+	WordPrefix   float64 `json:"wordPrefix"`   // Début d'un autre mot
+	Substring    float64 `json:"substring"`    // Milieu d'un mot
+	Fuzzy        float64 `json:"fuzzy"`        // Correspondance approchée
+	FuzzyPenalty float64 `json:"fuzzyPenalty"` // Retrait par faute de frappe (moyenne par mot de la requête)
+	Popularity   float64 `json:"popularity"`   // Poids de la popularité Spotify (0-100 ramené à 0-1)
+	Followers    float64 `json:"followers"`    // Poids des followers (échelle logarithmique ramenée à 0-1)
+}
+
+// DefaultRankingWeights : la position de la correspondance prime, la notoriété départage
+// (un préfixe d'artiste connu passe devant une sous-chaîne d'artiste obscur)
+var DefaultRankingWeights = RankingWeights{
+	Exact:        100,
+	Prefix:       80,
+	WordPrefix:   65,
+	Substring:    40,
+	Fuzzy:        35,
+	FuzzyPenalty: 10,
+	Popularity:   20,
+	Followers:    10,
+}
+
+// followersScale : 100 millions de followers correspondent à la note maximale (10^8)
+const followersScale = 8
+
+// Score calcule la note d'une correspondance pour un élément de popularité et de followers donnés
+func (w RankingWeights) Score(m Match, popularity, followers int) float64 {
+	var base float64
+	switch m.Kind {
+	case MatchExact:
+		base = w.Exact
+	case MatchPrefix:
+		base = w.Prefix
+	case MatchWordPrefix:
+		base = w.WordPrefix
+	case MatchSubstring:
+		base = w.Substring
+	case MatchFuzzy:
+		base = w.Fuzzy - w.FuzzyPenalty*m.Typos()
+	default:
+		return 0
+	}
+
+	pop := math.Min(math.Max(float64(popularity), 0), 100) / 100
+	fol := 0.0
+	if followers > 0 {
+		fol = math.Min(math.Log10(float64(followers))/followersScale, 1)
+	}
+
+	score := base + w.Popularity*pop + w.Followers*fol
+	// Arrondi pour un JSON lisible
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"groupie-tracker-ng/models"
+)
+
+// Release est une sortie (album, single...) d'un artiste du catalogue
+type Release struct {
+	AlbumID         string    `json:"albumId"`
+	Name            string    `json:"name"`
+	ReleaseDate     string    `json:"releaseDate"` // YYYY, YYYY-MM ou YYYY-MM-DD (Spotify)
+	ImageURL        string    `json:"imageUrl"`
+	SpotifyURL      string    `json:"spotifyUrl"`
+	TotalTracks     int       `json:"totalTracks"`
+	SpotifyArtistID string    `json:"spotifyArtistId"` // ID Spotify de l'artiste : stable, contrairement à l'ID du catalogue
+	ArtistName      string    `json:"artistName"`
+	Genres          []string  `json:"genres"`
+	FirstSeen       time.Time `json:"firstSeen"` // Date à laquelle la sortie a été vue pour la première fois
+}
+
+// key identifie une sortie pour un artiste (un album en collaboration apparaît pour chacun)
+func (r Release) key() string {
+	return r.AlbumID + "/" + r.artistKey()
+}
+
+// artistKey identifie l'artiste d'une sortie : son ID Spotify, ou son nom pour les sorties
+// enregistrées avant l'ID Spotify (reprises par Record au relevé suivant)
+func (r Release) artistKey() string {
+	if r.SpotifyArtistID == "" {
+		return "name:" + r.ArtistName
+	}
+	return r.SpotifyArtistID
+}
+
+// ReleaseStore mémorise les sorties déjà vues, sauvegardées dans un fichier JSON
+// pour qu'un redémarrage ne les annonce pas à nouveau
+type ReleaseStore struct {
+	mu       sync.RWMutex
+	path     string
+	releases map[string]Release
+}
+
+// NewReleaseStore ouvre le registre des sorties ; un fichier absent donne un registre vide
+func NewReleaseStore(path string) (*ReleaseStore, error) {
+	s := &ReleaseStore{path: path, releases: make(map[string]Release)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("lecture du registre des sorties : %w", err)
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return s, fmt.Errorf("registre des sorties illisible (%s) : %w", path, err)
+	}
+	for _, r := range releases {
+		s.releases[r.key()] = r
+	}
+	return s, nil
+}
+
+// Record enregistre les albums d'un artiste et retourne le nombre de sorties nouvelles
+func (s *ReleaseStore) Record(artist models.Artist, albums []models.AlbumInfo, now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := 0
+	for _, album := range albums {
+		if album.ID == "" {
+			continue
+		}
+		r := Release{
+			AlbumID:         album.ID,
+			Name:            album.Name,
+			ReleaseDate:     album.ReleaseDate,
+			ImageURL:        album.ImageURL,
+			SpotifyURL:      album.SpotifyURL,
+			TotalTracks:     album.TotalTracks,
+			SpotifyArtistID: artist.SpotifyID,
+			ArtistName:      artist.Name,
+			Genres:          artist.Genres,
+			FirstSeen:       now,
+		}
+		legacy := Release{AlbumID: album.ID, ArtistName: artist.Name}.key()
+		if known, ok := s.releases[r.key()]; ok {
+			// Déjà vue : garder la date de découverte, rafraîchir le reste (nom et genres de l'artiste)
+			r.FirstSeen = known.FirstSeen
+		} else if known, ok := s.releases[legacy]; ok && legacy != r.key() {
+			// Vue avant l'ID Spotify : reprise sous sa nouvelle clé, sans être annoncée à nouveau
+			r.FirstSeen = known.FirstSeen
+			delete(s.releases, legacy)
+		} else {
+			added++
+		}
+		s.releases[r.key()] = r
+	}
+	return added
+}
+
+// Save écrit le registre (fichier temporaire puis renommage : jamais de fichier à moitié écrit)
+func (s *ReleaseStore) Save() error {
+	s.mu.RLock()
+	releases := s.sorted(nil)
+	s.mu.RUnlock()
+
+	data, err := json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("création du dossier du registre : %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("écriture du registre des sorties : %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Latest retourne les sorties retenues par keep (toutes si nil), des plus récentes aux plus
+// anciennes, limit au plus (0 = pas de limite)
+func (s *ReleaseStore) Latest(keep func(Release) bool, limit int) []Release {
+	s.mu.RLock()
+	releases := s.sorted(keep)
+	s.mu.RUnlock()
+
+	if limit > 0 && len(releases) > limit {
+		releases = releases[:limit]
+	}
+	return releases
+}
+
+// sorted trie par date de découverte puis date de sortie décroissantes (appelant verrouillé).
+// Au premier passage tout est découvert en même temps : la date de sortie départage.
+func (s *ReleaseStore) sorted(keep func(Release) bool) []Release {
+	releases := make([]Release, 0, len(s.releases))
+	for _, r := range s.releases {
+		if keep == nil || keep(r) {
+			releases = append(releases, r)
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if !a.FirstSeen.Equal(b.FirstSeen) {
+			return a.FirstSeen.After(b.FirstSeen)
+		}
+		if a.ReleaseDate != b.ReleaseDate {
+			return a.ReleaseDate > b.ReleaseDate
+		}
+		return a.key() < b.key()
+	})
+	return releases
+}
+
+// ReleaseTime convertit une date de sortie Spotify (YYYY, YYYY-MM ou YYYY-MM-DD) en date
+func ReleaseTime(date string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
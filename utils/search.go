@@ -2,67 +2,88 @@ package utils
 
 import (
 	"groupie-tracker-ng/models"
-	"strings"
+	"sort"
 )
 
-// SearchArtists recherche des artistes selon une requête (nom d'artiste ou membre)
-func SearchArtists(artists []models.Artist, query string) []models.Artist {
-	queryLower := strings.ToLower(query)
-	filteredArtists := []models.Artist{}
-	
-	for _, artist := range artists {
-		// Rechercher dans le nom de l'artiste
-		if strings.Contains(strings.ToLower(artist.Name), queryLower) {
-			filteredArtists = append(filteredArtists, artist)
-			continue
+// Pondérations appliquées aux correspondances hors nom d'artiste
+const (
+	memberMatchWeight = 0.9 // Membre du groupe
+	albumMatchWeight  = 0.8 // Premier album
+)
+
+// ScoredArtist associe un artiste à la qualité de sa correspondance avec la requête
+type ScoredArtist struct {
+	Artist models.Artist
+	Score  float64
+}
+
+// ScoreArtist note un artiste pour une requête déjà repliée (0 = aucune correspondance).
+// La meilleure correspondance entre le nom, les membres et le premier album l'emporte.
+func ScoreArtist(artist models.Artist, foldedQuery string) float64 {
+	best := MatchText(FoldText(artist.Name), foldedQuery).Quality()
+
+	for _, member := range artist.Members {
+		if q := MatchText(FoldText(member), foldedQuery).Quality() * memberMatchWeight; q > best {
+			best = q
 		}
-		
-		// Rechercher dans les membres
-		for _, member := range artist.Members {
-			if strings.Contains(strings.ToLower(member), queryLower) {
-				filteredArtists = append(filteredArtists, artist)
-				break
-			}
+	}
+
+	if artist.FirstAlbum != "" {
+		if q := MatchText(FoldText(artist.FirstAlbum), foldedQuery).Quality() * albumMatchWeight; q > best {
+			best = q
 		}
 	}
-	
-	return filteredArtists
+
+	return best
 }
 
-// GetSuggestions génère des suggestions pour la barre de recherche
-func GetSuggestions(artists []models.Artist, query string) []models.Artist {
-	queryLower := strings.ToLower(query)
-	suggestions := []models.Artist{}
-	seen := make(map[int]bool)
-	
-	for _, artist := range artists {
-		if seen[artist.ID] {
-			continue
-		}
-		
-		// Rechercher dans le nom de l'artiste
-		if strings.Contains(strings.ToLower(artist.Name), queryLower) {
-			suggestions = append(suggestions, artist)
-			seen[artist.ID] = true
-			continue
+// RankArtists retourne les artistes correspondant à la requête, du meilleur au moins bon.
+// À qualité égale, l'ordre du catalogue est conservé. Une requête vide une fois repliée
+// (ponctuation seule, comme "!!") ne filtre pas : tous les artistes sont retournés.
+func RankArtists(artists []models.Artist, query string) []ScoredArtist {
+	foldedQuery := FoldText(query)
+	ranked := []ScoredArtist{}
+	if foldedQuery == "" {
+		for _, artist := range artists {
+			ranked = append(ranked, ScoredArtist{Artist: artist})
 		}
-		
-		// Rechercher dans les membres
-		for _, member := range artist.Members {
-			if strings.Contains(strings.ToLower(member), queryLower) {
-				if !seen[artist.ID] {
-					suggestions = append(suggestions, artist)
-					seen[artist.ID] = true
-				}
-				break
-			}
+		return ranked
+	}
+
+	for _, artist := range artists {
+		if score := ScoreArtist(artist, foldedQuery); score > 0 {
+			ranked = append(ranked, ScoredArtist{Artist: artist, Score: score})
 		}
 	}
-	
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	return ranked
+}
+
+// SearchArtists recherche des artistes selon une requête (nom d'artiste, membre ou premier album).
+// Insensible à la casse et aux accents, tolérante aux fautes de frappe, triée par pertinence.
+func SearchArtists(artists []models.Artist, query string) []models.Artist {
+	ranked := RankArtists(artists, query)
+
+	filteredArtists := make([]models.Artist, len(ranked))
+	for i, r := range ranked {
+		filteredArtists[i] = r.Artist
+	}
+
+	return filteredArtists
+}
+
+// GetSuggestions génère des suggestions pour la barre de recherche
+func GetSuggestions(artists []models.Artist, query string) []models.Artist {
+	suggestions := SearchArtists(artists, query)
+
 	// Limiter à 10 suggestions
 	if len(suggestions) > 10 {
 		suggestions = suggestions[:10]
 	}
-	
+
 	return suggestions
 }
@@ -0,0 +1,273 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"groupie-tracker-ng/models"
+)
+
+// indexEntry est une valeur indexée (nom, membre, album, genre...) rattachée à son type
+type indexEntry struct {
+	kind     string
+	value    string // Valeur d'origine affichée
+	folded   string // Valeur repliée par FoldText
+	artistID int    // Renseigné pour les entrées de type artiste uniquement
+	// Notoriété (maximum parmi les artistes concernés) utilisée pour le classement
+	popularity int
+	followers  int
+}
+
+// SearchIndex est un index inversé mot -> entrées, avec recherche par préfixe.
+// Il est immuable une fois construit : on en reconstruit un nouveau à chaque rafraîchissement.
+type SearchIndex struct {
+	entries []indexEntry
+	tokens  map[string][]int // mot replié -> positions dans entries
+	vocab   []string         // mots triés (recherche par préfixe par dichotomie)
+	// Index de suppressions pour la tolérance aux fautes : chaîne obtenue en retirant quelques
+	// lettres d'un mot (ou d'un de ses préfixes) -> positions du mot dans vocab
+	deletions map[string][]int
+	builtAt   time.Time
+}
+
+// BuildSearchIndex indexe les noms, membres, albums, genres, années de création
+// et dates de premier album des artistes, ainsi que les lieux de concerts
+func BuildSearchIndex(artists []models.Artist, locations *LocationIndex) *SearchIndex {
+	idx := &SearchIndex{
+		tokens:  make(map[string][]int),
+		builtAt: time.Now(),
+	}
+
+	seen := make(map[string]int) // clé de dédoublonnage -> position dans entries
+	for _, artist := range artists {
+		add := func(kind, value string, artistID int) {
+			folded := FoldText(value)
+			if folded == "" {
+				return
+			}
+			// Dédoublonner (les artistes homonymes restent distincts)
+			key := kind + "\x00" + folded
+			if kind == SuggestionArtist {
+				key += "\x00" + strconv.Itoa(artistID)
+			}
+			if pos, ok := seen[key]; ok {
+				entry := &idx.entries[pos]
+				entry.popularity = max(entry.popularity, artist.Popularity)
+				entry.followers = max(entry.followers, artist.Followers)
+				return
+			}
+
+			seen[key] = idx.addEntry(indexEntry{
+				kind:       kind,
+				value:      value,
+				folded:     folded,
+				artistID:   artistID,
+				popularity: artist.Popularity,
+				followers:  artist.Followers,
+			})
+		}
+
+		add(SuggestionArtist, artist.Name, artist.ID)
+		for _, member := range artist.Members {
+			add(SuggestionMember, member, 0)
+		}
+		add(SuggestionAlbum, artist.FirstAlbum, 0)
+		for _, genre := range artist.Genres {
+			add(SuggestionGenre, genre, 0)
+		}
+		if artist.CreationDate > 0 {
+			add(SuggestionCreationYear, strconv.Itoa(artist.CreationDate), 0)
+		}
+		add(SuggestionFirstAlbumDate, artist.FirstAlbumDate, 0)
+	}
+	for _, location := range locations.Popular() {
+		idx.addEntry(indexEntry{kind: SuggestionLocation, value: location, folded: FoldText(location)})
+	}
+
+	idx.vocab = make([]string, 0, len(idx.tokens))
+	for token := range idx.tokens {
+		idx.vocab = append(idx.vocab, token)
+	}
+	sort.Strings(idx.vocab)
+	idx.buildDeletions()
+
+	return idx
+}
+
+// addEntry ajoute une entrée et l'indexe sous chacun de ses mots
+func (idx *SearchIndex) addEntry(entry indexEntry) int {
+	pos := len(idx.entries)
+	idx.entries = append(idx.entries, entry)
+	for _, token := range uniqueTokens(entry.folded) {
+		idx.tokens[token] = append(idx.tokens[token], pos)
+	}
+	return pos
+}
+
+// maxFuzzyPrefix borne la longueur des préfixes indexés pour la tolérance aux fautes :
+// au-delà, un mot saisi avec une faute n'est comparé qu'aux mots complets
+const maxFuzzyPrefix = 12
+
+// buildDeletions indexe, pour chaque mot du vocabulaire, les chaînes obtenues en retirant des
+// lettres au mot complet (2 au plus) et à ses préfixes d'au moins 4 lettres (autant que de
+// fautes tolérées pour un mot saisi de cette longueur). Deux mots à quelques fautes près ont
+// une suppression commune : une requête ne compare plus que ces candidats, quelle que soit la
+// taille du catalogue.
+func (idx *SearchIndex) buildDeletions() {
+	idx.deletions = make(map[string][]int)
+	for i, token := range idx.vocab {
+		keys := make(map[string]bool)
+		runes := []rune(token)
+		addDeletions(keys, runes, maxTypos)
+		for n := 4; n < len(runes) && n <= maxFuzzyPrefix; n++ {
+			addDeletions(keys, runes[:n], typoTolerance(string(runes[:n])))
+		}
+		for key := range keys {
+			idx.deletions[key] = append(idx.deletions[key], i)
+		}
+	}
+}
+
+// addDeletions ajoute à keys le mot et toutes les chaînes obtenues en lui retirant
+// jusqu'à n lettres
+func addDeletions(keys map[string]bool, word []rune, n int) {
+	keys[string(word)] = true
+	if n == 0 || len(word) <= 1 {
+		return
+	}
+	for i := range word {
+		shorter := make([]rune, 0, len(word)-1)
+		shorter = append(shorter, word[:i]...)
+		shorter = append(shorter, word[i+1:]...)
+		addDeletions(keys, shorter, n-1)
+	}
+}
+
+// uniqueTokens découpe un texte replié en mots distincts
+func uniqueTokens(folded string) []string {
+	words := strings.Fields(folded)
+	seen := make(map[string]bool, len(words))
+	tokens := words[:0]
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// BuiltAt retourne la date de construction de l'index
+func (idx *SearchIndex) BuiltAt() time.Time {
+	if idx == nil {
+		return time.Time{}
+	}
+	return idx.builtAt
+}
+
+// Len retourne le nombre d'entrées indexées
+func (idx *SearchIndex) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.entries)
+}
+
+// SuggestOptions règle le nombre et le classement des suggestions
+type SuggestOptions struct {
+	Limit   int            // Nombre maximum de suggestions (0 = pas de limite)
+	Weights RankingWeights // Pondérations du classement
+}
+
+// DefaultSuggestLimit est le nombre de suggestions retournées par défaut
+const DefaultSuggestLimit = 10
+
+// Suggest retourne les suggestions typées pour une requête, de la meilleure note à la moins bonne.
+// Chaque mot de la requête est cherché par préfixe dans l'index ; à défaut, avec tolérance
+// aux fautes de frappe grâce à l'index de suppressions.
+func (idx *SearchIndex) Suggest(query string, opts SuggestOptions) []Suggestion {
+	foldedQuery := FoldText(query)
+	suggestions := []Suggestion{}
+	if foldedQuery == "" {
+		return suggestions
+	}
+
+	for _, pos := range idx.candidates(foldedQuery) {
+		entry := idx.entries[pos]
+		if score := opts.Weights.Score(MatchText(entry.folded, foldedQuery), entry.popularity, entry.followers); score > 0 {
+			suggestions = append(suggestions, newSuggestion(entry.kind, entry.value, entry.artistID, score))
+		}
+	}
+
+	return rankSuggestions(suggestions, opts.Limit)
+}
+
+// candidates retourne les entrées contenant tous les mots de la requête (intersection des listes)
+func (idx *SearchIndex) candidates(foldedQuery string) []int {
+	if idx == nil {
+		return nil
+	}
+
+	var result map[int]bool
+	for _, word := range uniqueTokens(foldedQuery) {
+		matches := idx.lookup(word)
+		if result == nil {
+			result = matches
+			continue
+		}
+		for pos := range result {
+			if !matches[pos] {
+				delete(result, pos)
+			}
+		}
+	}
+
+	positions := make([]int, 0, len(result))
+	for pos := range result {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions) // Ordre d'indexation = ordre du catalogue
+	return positions
+}
+
+// lookup retourne les entrées dont un mot commence par word, ou à défaut s'en approche
+func (idx *SearchIndex) lookup(word string) map[int]bool {
+	matches := make(map[int]bool)
+
+	start := sort.SearchStrings(idx.vocab, word)
+	for i := start; i < len(idx.vocab) && strings.HasPrefix(idx.vocab[i], word); i++ {
+		for _, pos := range idx.tokens[idx.vocab[i]] {
+			matches[pos] = true
+		}
+	}
+	if len(matches) > 0 {
+		return matches
+	}
+
+	// Aucun préfixe exact : tolérer les fautes de frappe. Seuls les mots partageant une
+	// suppression avec le mot saisi sont comparés, sans parcourir le vocabulaire.
+	tolerance := typoTolerance(word)
+	if tolerance == 0 {
+		return matches
+	}
+	keys := make(map[string]bool)
+	addDeletions(keys, []rune(word), tolerance)
+	compared := make(map[int]bool)
+	for key := range keys {
+		for _, i := range idx.deletions[key] {
+			if compared[i] {
+				continue
+			}
+			compared[i] = true
+			token := idx.vocab[i]
+			if _, ok := fuzzyDistance(token, word); ok {
+				for _, pos := range idx.tokens[token] {
+					matches[pos] = true
+				}
+			}
+		}
+	}
+	return matches
+}
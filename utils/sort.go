@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	"groupie-tracker-ng/models"
+)
+
+// SortOption représente un tri proposé dans l'interface
+type SortOption struct {
+	Value string // Valeur du paramètre "sort" (préfixe "-" = décroissant)
+	Label string // Libellé en français ; les pages affichent la traduction "sort.<Value>" du catalogue
+}
+
+// SortOptions liste les tris disponibles, dans l'ordre d'affichage
+var SortOptions = []SortOption{
+	{"name", "Nom (A → Z)"},
+	{"-name", "Nom (Z → A)"},
+	{"-popularity", "Popularité (décroissante)"},
+	{"popularity", "Popularité (croissante)"},
+	{"-followers", "Followers (décroissant)"},
+	{"followers", "Followers (croissant)"},
+	{"creationDate", "Création (plus ancien)"},
+	{"-creationDate", "Création (plus récent)"},
+	{"firstAlbumDate", "Premier album (plus ancien)"},
+	{"-firstAlbumDate", "Premier album (plus récent)"},
+}
+
+// IsValidSort vérifie qu'une valeur de tri fait partie des options connues
+func IsValidSort(value string) bool {
+	for _, option := range SortOptions {
+		if option.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SortArtists trie une copie des artistes selon la clé ("name", "-popularity", ...).
+// Une clé vide ou inconnue conserve l'ordre du catalogue. Les dates inconnues sont toujours en fin de liste.
+func SortArtists(artists []models.Artist, sortKey string) []models.Artist {
+	sorted := make([]models.Artist, len(artists))
+	copy(sorted, artists)
+
+	if !IsValidSort(sortKey) {
+		return sorted
+	}
+
+	desc := strings.HasPrefix(sortKey, "-")
+	field := strings.TrimPrefix(sortKey, "-")
+
+	// compare retourne <0, 0 ou >0 ; known indique si la valeur est renseignée
+	var compare func(a, b models.Artist) int
+	known := func(models.Artist) bool { return true }
+
+	switch field {
+	case "name":
+		compare = func(a, b models.Artist) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		}
+	case "popularity":
+		compare = func(a, b models.Artist) int { return a.Popularity - b.Popularity }
+	case "followers":
+		compare = func(a, b models.Artist) int { return a.Followers - b.Followers }
+	case "creationDate":
+		compare = func(a, b models.Artist) int { return a.CreationDate - b.CreationDate }
+		known = func(a models.Artist) bool { return a.CreationDate > 0 }
+	case "firstAlbumDate":
+		compare = func(a, b models.Artist) int {
+			da, _ := parseSpotifyDate(a.FirstAlbumDate)
+			db, _ := parseSpotifyDate(b.FirstAlbumDate)
+			return da.Compare(db)
+		}
+		known = func(a models.Artist) bool {
+			_, err := parseSpotifyDate(a.FirstAlbumDate)
+			return err == nil
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ki, kj := known(sorted[i]), known(sorted[j])
+		if ki != kj {
+			return ki
+		}
+		c := compare(sorted[i], sorted[j])
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	return sorted
+}
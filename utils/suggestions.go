@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+
+	"groupie-tracker-ng/models"
+)
+
+// Types de suggestions proposés par la barre de recherche
+const (
+	SuggestionArtist         = "artist"
+	SuggestionMember         = "member"
+	SuggestionAlbum          = "album"
+	SuggestionGenre          = "genre"
+	SuggestionLocation       = "location"
+	SuggestionCreationYear   = "creationYear"
+	SuggestionFirstAlbumDate = "firstAlbumDate"
+)
+
+// suggestionTypeOrder départage deux suggestions de même qualité (artistes d'abord)
+var suggestionTypeOrder = map[string]int{
+	SuggestionArtist:         0,
+	SuggestionMember:         1,
+	SuggestionAlbum:          2,
+	SuggestionGenre:          3,
+	SuggestionLocation:       4,
+	SuggestionCreationYear:   5,
+	SuggestionFirstAlbumDate: 6,
+}
+
+// Suggestion représente une suggestion typée de la barre de recherche
+type Suggestion struct {
+	Type      string  `json:"type"`
+	Value     string  `json:"value"`     // Texte correspondant ("Freddie Mercury")
+	TypeLabel string  `json:"typeLabel"` // Libellé du type ("membre")
+	Label     string  `json:"label"`     // Libellé complet ("Freddie Mercury — membre")
+	URL       string  `json:"url"`       // Page de destination (fiche artiste ou résultats filtrés)
+	ID        int     `json:"id,omitempty"`
+	Score     float64 `json:"score"` // Note de classement (voir RankingWeights)
+}
+
+// Localize traduit le libellé du type (clé "suggestion.<type>" du catalogue) et le libellé complet
+func (s *Suggestion) Localize(lang string) {
+	s.TypeLabel = Messages.T(lang, "suggestion."+s.Type)
+	s.Label = s.Value + " — " + s.TypeLabel
+}
+
+// newSuggestion construit une suggestion et son URL de destination
+func newSuggestion(kind, value string, artistID int, score float64) Suggestion {
+	s := Suggestion{
+		Type:  kind,
+		Value: value,
+		Score: score,
+	}
+	s.Localize(DefaultLanguage)
+
+	params := url.Values{}
+	switch kind {
+	case SuggestionArtist:
+		s.ID = artistID
+		s.URL = "/artist/" + strconv.Itoa(artistID)
+		return s
+	case SuggestionMember, SuggestionAlbum:
+		params.Set("q", value)
+		s.URL = "/search?" + params.Encode()
+		return s
+	case SuggestionGenre:
+		params.Set("genre", value)
+	case SuggestionLocation:
+		params.Set("location", value)
+	case SuggestionCreationYear:
+		params.Set("minYear", value)
+		params.Set("maxYear", value)
+	case SuggestionFirstAlbumDate:
+		params.Set("firstAlbumMin", value)
+		params.Set("firstAlbumMax", value)
+	}
+	s.URL = "/artists?" + params.Encode()
+	return s
+}
+
+// BuildSuggestions génère des suggestions typées (artiste, membre, album, genre, lieu,
+// année de création, date du premier album), classées avec les pondérations par défaut.
+// Pour des requêtes répétées, construire un SearchIndex une fois et appeler Suggest.
+func BuildSuggestions(artists []models.Artist, query string, locations *LocationIndex, limit int) []Suggestion {
+	return BuildSearchIndex(artists, locations).Suggest(query, SuggestOptions{Limit: limit, Weights: DefaultRankingWeights})
+}
+
+// rankSuggestions trie les suggestions par note puis par type, et applique la limite
+func rankSuggestions(suggestions []Suggestion, limit int) []Suggestion {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestionTypeOrder[suggestions[i].Type] < suggestionTypeOrder[suggestions[j].Type]
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions
+}
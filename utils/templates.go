@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"path"
+	"sync"
+	"time"
+)
+
+// layoutTemplate est le gabarit commun des pages ; il définit "layout"
+const layoutTemplate = "layout.html"
+
+// Templates est l'ensemble des templates du serveur (installé au démarrage)
+var Templates *TemplateSet
+
+// TemplateSet est l'ensemble des templates compilés : chaque page est associée au gabarit
+// commun, une fois pour toutes et pour chaque langue (fonctions "t", "formatNumber"... liées
+// à la langue). En mode développement, les fichiers modifiés sont relus.
+type TemplateSet struct {
+	fsys      fs.FS
+	funcs     func(lang string) template.FuncMap
+	languages []string
+	dev       bool
+
+	mu       sync.RWMutex
+	pages    map[string]map[string]*template.Template // langue -> page -> template
+	modified time.Time                                // Dernière modification des fichiers compilés (mode développement)
+}
+
+// NewTemplateSet compile tous les templates (*.html) de fsys dans chaque langue, avec les
+// fonctions retournées par funcs. Une erreur de syntaxe est signalée ici, au démarrage, et non
+// à la première visite de la page. Avec dev, les fichiers sont surveillés et recompilés à la
+// première requête qui suit une modification.
+func NewTemplateSet(fsys fs.FS, funcs func(lang string) template.FuncMap, languages []string, dev bool) (*TemplateSet, error) {
+	ts := &TemplateSet{fsys: fsys, funcs: funcs, languages: languages, dev: dev}
+	pages, modified, err := ts.parse()
+	if err != nil {
+		return nil, err
+	}
+	ts.pages, ts.modified = pages, modified
+	return ts, nil
+}
+
+// parse compile chaque page avec le gabarit commun, dans chaque langue
+func (ts *TemplateSet) parse() (map[string]map[string]*template.Template, time.Time, error) {
+	names, err := fs.Glob(ts.fsys, "*.html")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	modified, err := ts.lastModified(names)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	pages := make(map[string]map[string]*template.Template, len(ts.languages))
+	for _, lang := range ts.languages {
+		pages[lang] = make(map[string]*template.Template, len(names))
+		funcs := ts.funcs(lang)
+		for _, name := range names {
+			if name == layoutTemplate {
+				continue
+			}
+			tmpl, err := template.New(name).Funcs(funcs).ParseFS(ts.fsys, layoutTemplate, name)
+			if err != nil {
+				return nil, time.Time{}, fmt.Errorf("template %s : %w", name, err)
+			}
+			pages[lang][name] = tmpl
+		}
+	}
+	return pages, modified, nil
+}
+
+// lastModified retourne la date de modification la plus récente des fichiers
+func (ts *TemplateSet) lastModified(names []string) (time.Time, error) {
+	var latest time.Time
+	for _, name := range names {
+		info, err := fs.Stat(ts.fsys, name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// Lookup retourne une page compilée dans une langue (ex. "en", "artists.html"), ou dans la
+// langue par défaut si celle-ci n'est pas disponible. La page s'exécute avec
+// ExecuteTemplate(w, "layout", data), sauf error.html qui est autonome (Execute).
+func (ts *TemplateSet) Lookup(lang, name string) (*template.Template, error) {
+	if ts == nil {
+		return nil, fmt.Errorf("templates non chargés (template %s)", name)
+	}
+	if ts.dev {
+		if err := ts.reloadIfChanged(); err != nil {
+			return nil, err
+		}
+	}
+
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	pages, ok := ts.pages[lang]
+	if !ok {
+		pages = ts.pages[DefaultLanguage]
+	}
+	tmpl, ok := pages[path.Base(name)]
+	if !ok {
+		return nil, fmt.Errorf("template %s introuvable", name)
+	}
+	return tmpl, nil
+}
+
+// reloadIfChanged recompile les templates si un fichier a été modifié, ajouté ou supprimé
+func (ts *TemplateSet) reloadIfChanged() error {
+	names, err := fs.Glob(ts.fsys, "*.html")
+	if err != nil {
+		return err
+	}
+	modified, err := ts.lastModified(names)
+	if err != nil {
+		return err
+	}
+
+	ts.mu.RLock()
+	unchanged := modified.Equal(ts.modified) && len(names) == len(ts.pages[DefaultLanguage])+1
+	ts.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	pages, modified, err := ts.parse()
+	if err != nil {
+		// Erreur affichée à la place de la page ; la prochaine requête retentera après correction
+		return err
+	}
+	ts.mu.Lock()
+	ts.pages, ts.modified = pages, modified
+	ts.mu.Unlock()
+	log.Printf("♻️ Templates rechargés")
+	return nil
+}